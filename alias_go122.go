@@ -0,0 +1,11 @@
+//go:build go1.22
+
+package symbolassert
+
+import "go/types"
+
+// unalias returns the type an alias refers to, following
+// alias chains. Other types are returned as is.
+func unalias(t types.Type) types.Type {
+	return types.Unalias(t)
+}
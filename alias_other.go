@@ -0,0 +1,11 @@
+//go:build !go1.22
+
+package symbolassert
+
+import "go/types"
+
+// unalias returns t, aliases are not materialized
+// before Go 1.22.
+func unalias(t types.Type) types.Type {
+	return t
+}
@@ -0,0 +1,279 @@
+package symbolassert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A BuildPlan proposes the files that declare symbols with
+// the values of each target, see PlanBuild.
+type BuildPlan struct {
+	Files []*PlanFile
+}
+
+// A PlanFile is a file of a BuildPlan.
+type PlanFile struct {
+	Name string
+
+	// Constraint is the //go:build expression of the file,
+	// it's empty for the file shared by all targets.
+	Constraint string
+	Targets    []Target
+
+	Symbols []PlanSymbol
+}
+
+// A PlanSymbol is a symbol declared by a PlanFile.
+type PlanSymbol struct {
+	Symbol string
+	Value  string
+}
+
+// PlanBuild proposes files with build constraints that
+// declare every symbol of the table with the value it has
+// on each target. Symbols that have the same value on all
+// targets go to a shared file named base.go. The others go
+// to files for the targets that share their value, or for
+// the targets that share all values, whichever takes fewer
+// files; the plan isn't necessarily the smallest. A file is
+// named for its constraint if the constraint is a GOOS or
+// a GOOS and GOARCH, like base_linux_arm64.go. A symbol that
+// is undefined on some targets isn't declared on them.
+//
+// The constraints only distinguish the targets of the table,
+// a file for linux may be used on a linux port that isn't
+// in the table.
+func PlanBuild(vt *ValueTable, base string) *BuildPlan {
+	all := vt.Targets
+	shared := &PlanFile{Name: base + ".go", Targets: all}
+
+	// classes of targets with the same value of a symbol
+	type class struct {
+		key   string
+		value string
+	}
+	classes := make(map[string][]int) // key to target indexes
+	symbolClasses := make([][]class, len(vt.Rows))
+	var divergent []int // rows
+	for i, row := range vt.Rows {
+		byValue := make(map[string][]int)
+		var values []string
+		for j, c := range row.Cells {
+			if !c.Defined {
+				continue
+			}
+			if _, ok := byValue[c.Value]; !ok {
+				values = append(values, c.Value)
+			}
+			byValue[c.Value] = append(byValue[c.Value], j)
+		}
+		if len(values) == 1 && len(byValue[values[0]]) == len(all) {
+			shared.Symbols = append(shared.Symbols, PlanSymbol{row.Symbol, values[0]})
+			continue
+		}
+		divergent = append(divergent, i)
+		for _, v := range values {
+			key := indexKey(byValue[v])
+			classes[key] = byValue[v]
+			symbolClasses[i] = append(symbolClasses[i], class{key, v})
+		}
+	}
+
+	// Targets that have the same values for all symbols are
+	// atoms, every class is a union of atoms. Either the
+	// classes or the atoms are used, whichever are fewer.
+	signatures := make(map[string][]int)
+	for j := range all {
+		var sig strings.Builder
+		for _, i := range divergent {
+			c := vt.Rows[i].Cells[j]
+			fmt.Fprintf(&sig, "%t%q;", c.Defined, c.Value)
+		}
+		signatures[sig.String()] = append(signatures[sig.String()], j)
+	}
+	useAtoms := len(signatures) < len(classes)
+	atomOf := make(map[int][]int) // target index to its atom
+	for _, atom := range signatures {
+		for _, j := range atom {
+			atomOf[j] = atom
+		}
+	}
+
+	files := make(map[string]*PlanFile)
+	file := func(indexes []int) *PlanFile {
+		key := indexKey(indexes)
+		if f, ok := files[key]; ok {
+			return f
+		}
+		targets := make([]Target, len(indexes))
+		for i, j := range indexes {
+			targets[i] = all[j]
+		}
+		f := &PlanFile{
+			Constraint: constraintExpr(targets, all),
+			Targets:    targets,
+		}
+		f.Name = planFileName(base, f.Constraint, targets)
+		files[key] = f
+		return f
+	}
+	for _, i := range divergent {
+		for _, c := range symbolClasses[i] {
+			sym := PlanSymbol{vt.Rows[i].Symbol, c.value}
+			if !useAtoms {
+				f := file(classes[c.key])
+				f.Symbols = append(f.Symbols, sym)
+				continue
+			}
+			seen := make(map[string]bool)
+			for _, j := range classes[c.key] {
+				atom := atomOf[j]
+				if key := indexKey(atom); !seen[key] {
+					seen[key] = true
+					f := file(atom)
+					f.Symbols = append(f.Symbols, sym)
+				}
+			}
+		}
+	}
+
+	plan := &BuildPlan{}
+	if len(shared.Symbols) > 0 {
+		plan.Files = append(plan.Files, shared)
+	}
+	var rest []*PlanFile
+	for _, f := range files {
+		rest = append(rest, f)
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		return rest[i].Name < rest[j].Name
+	})
+	plan.Files = append(plan.Files, rest...)
+	return plan
+}
+
+// String formats the plan for review.
+func (p *BuildPlan) String() string {
+	var b strings.Builder
+	for i, f := range p.Files {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(f.Name)
+		if f.Constraint != "" {
+			fmt.Fprintf(&b, " (//go:build %s)", f.Constraint)
+		}
+		b.WriteString("\n")
+		for _, s := range f.Symbols {
+			fmt.Fprintf(&b, "\t%s = %s\n", s.Symbol, s.Value)
+		}
+	}
+	return b.String()
+}
+
+func indexKey(indexes []int) string {
+	return fmt.Sprint(indexes)
+}
+
+// planFileName returns the name of the file for targets,
+// which only implies the build constraint of the file if
+// it's a GOOS or a GOOS and GOARCH.
+func planFileName(base, constraint string, targets []Target) string {
+	switch t := targets[0]; constraint {
+	case t.GOOS:
+		return base + "_" + t.GOOS + ".go"
+	case t.GOOS + " && " + t.GOARCH:
+		return base + "_" + t.GOOS + "_" + t.GOARCH + ".go"
+	}
+	return base + "_" + targetNames(targets) + ".go"
+}
+
+// targetNames joins the targets with hyphens, so the file
+// name doesn't imply a build constraint.
+func targetNames(targets []Target) string {
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		names[i] = t.GOOS + "-" + t.GOARCH
+	}
+	return strings.Join(names, "-")
+}
+
+func hasExtra(targets []Target) bool {
+	for _, t := range targets {
+		if t.BuildTags != "" || t.CgoEnabled {
+			return true
+		}
+	}
+	return false
+}
+
+// constraintExpr returns a build constraint expression that
+// is satisfied by targets and no other target of all. Targets
+// are grouped by GOOS, a GOOS is used alone if all its targets
+// are included.
+func constraintExpr(targets, all []Target) string {
+	if len(targets) == len(all) {
+		return ""
+	}
+	if hasExtra(targets) || hasExtra(all) {
+		terms := make([]string, len(targets))
+		for i, t := range targets {
+			terms[i] = targetTerm(t)
+		}
+		return joinTerms(terms)
+	}
+	total := make(map[string]int)
+	for _, t := range all {
+		total[t.GOOS]++
+	}
+	archs := make(map[string][]string)
+	var systems []string
+	for _, t := range targets {
+		if _, ok := archs[t.GOOS]; !ok {
+			systems = append(systems, t.GOOS)
+		}
+		archs[t.GOOS] = append(archs[t.GOOS], t.GOARCH)
+	}
+	sort.Strings(systems)
+
+	terms := make([]string, 0, len(systems))
+	for _, goos := range systems {
+		a := archs[goos]
+		sort.Strings(a)
+		switch {
+		case len(a) == total[goos]:
+			terms = append(terms, goos)
+		case len(a) == 1:
+			terms = append(terms, goos+" && "+a[0])
+		default:
+			terms = append(terms, goos+" && ("+strings.Join(a, " || ")+")")
+		}
+	}
+	return joinTerms(terms)
+}
+
+func targetTerm(t Target) string {
+	terms := []string{t.GOOS, t.GOARCH}
+	if t.BuildTags != "" {
+		terms = append(terms, strings.Split(t.BuildTags, ",")...)
+	}
+	if t.CgoEnabled {
+		terms = append(terms, "cgo")
+	} else {
+		terms = append(terms, "!cgo")
+	}
+	return strings.Join(terms, " && ")
+}
+
+func joinTerms(terms []string) string {
+	if len(terms) == 1 {
+		return terms[0]
+	}
+	for i, t := range terms {
+		if strings.Contains(t, " ") {
+			terms[i] = "(" + t + ")"
+		}
+	}
+	return strings.Join(terms, " || ")
+}
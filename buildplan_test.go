@@ -0,0 +1,86 @@
+package symbolassert
+
+import "testing"
+
+func TestPlanBuild(t *testing.T) {
+	targets := []Target{
+		{GOOS: "darwin", GOARCH: "arm64"},
+		{GOOS: "linux", GOARCH: "386"},
+		{GOOS: "linux", GOARCH: "amd64"},
+		{GOOS: "windows", GOARCH: "amd64"},
+	}
+	row := func(symbol string, values ...string) ValueRow {
+		r := ValueRow{Symbol: symbol}
+		for _, v := range values {
+			r.Cells = append(r.Cells, ValueCell{Value: v, Defined: v != ""})
+		}
+		return r
+	}
+
+	t.Run("Classes", func(t *testing.T) {
+		vt := &ValueTable{Targets: targets, Rows: []ValueRow{
+			row("AF_INET", "2", "2", "2", "2"),
+			row("AF_INET6", "30", "10", "10", "23"),
+			row("O_CLOEXEC", "", "0x80000", "0x80000", ""),
+		}}
+		want := `mirror.go
+	AF_INET = 2
+
+mirror_darwin.go (//go:build darwin)
+	AF_INET6 = 30
+
+mirror_linux.go (//go:build linux)
+	AF_INET6 = 10
+	O_CLOEXEC = 0x80000
+
+mirror_windows.go (//go:build windows)
+	AF_INET6 = 23
+`
+		if got := PlanBuild(vt, "mirror").String(); got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("Atoms", func(t *testing.T) {
+		// four classes of two symbols, three atoms
+		vt := &ValueTable{Targets: targets[1:], Rows: []ValueRow{
+			row("A", "1", "1", "2"),
+			row("B", "1", "2", "2"),
+		}}
+		want := `mirror_linux_386.go (//go:build linux && 386)
+	A = 1
+	B = 1
+
+mirror_linux_amd64.go (//go:build linux && amd64)
+	A = 1
+	B = 2
+
+mirror_windows.go (//go:build windows)
+	A = 2
+	B = 2
+`
+		if got := PlanBuild(vt, "mirror").String(); got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("Group", func(t *testing.T) {
+		vt := &ValueTable{Targets: targets, Rows: []ValueRow{
+			row("Uintptr", "8", "4", "8", "8"),
+		}}
+		plan := PlanBuild(vt, "mirror")
+		if len(plan.Files) != 2 {
+			t.Fatalf("got %d files, want 2:\n%s", len(plan.Files), plan)
+		}
+		f := plan.Files[0]
+		if want := "mirror_darwin-arm64-linux-amd64-windows-amd64.go"; f.Name != want {
+			t.Errorf("got name %s, want %s", f.Name, want)
+		}
+		if want := "darwin || (linux && amd64) || windows"; f.Constraint != want {
+			t.Errorf("got constraint %s, want %s", f.Constraint, want)
+		}
+		if want := "mirror_linux_386.go"; plan.Files[1].Name != want {
+			t.Errorf("got name %s, want %s", plan.Files[1].Name, want)
+		}
+	})
+}
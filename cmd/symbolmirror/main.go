@@ -0,0 +1,102 @@
+// Command symbolmirror generates the source of a package that
+// mirrors symbols of an authoritative package on a list of
+// platforms, see symbolassert.Generator. It's meant to be run
+// by go generate:
+//
+//	//go:generate go run github.com/dwlnetnl/symbolassert/cmd/symbolmirror -from golang.org/x/sys/unix AF_*=AF_* Stat_t=stat Timespec=timespec
+//
+// The arguments map authoritative symbols to local names,
+// an argument without a local name keeps the name. The types
+// a mapped type refers to must be mapped too, like Timespec
+// that Stat_t has fields of.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dwlnetnl/symbolassert"
+)
+
+var transforms = map[string]symbolassert.NameTransform{
+	"lower":        symbolassert.Lower,
+	"upper":        symbolassert.Upper,
+	"lowerfirst":   symbolassert.LowerFirst,
+	"upperfirst":   symbolassert.UpperFirst,
+	"snaketocamel": symbolassert.SnakeToCamel,
+}
+
+func main() {
+	var (
+		from      = flag.String("from", "", "import path of the authoritative `package`")
+		pkg       = flag.String("pkg", os.Getenv("GOPACKAGE"), "`name` of the generated package")
+		base      = flag.String("base", "mirror", "`name` of the generated files without suffix")
+		dir       = flag.String("o", ".", "output `directory`")
+		targets   = flag.String("targets", "", "comma-separated `goos/goarch` list, the first-class ports if empty")
+		transform = flag.String("transform", "", "comma-separated `transforms` of the names patterns expand to: "+transformNames())
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: symbolmirror -from package [flags] symbol[=local]...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *from == "" || *pkg == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	g := &symbolassert.Generator{
+		From:    *from,
+		Package: *pkg,
+		Base:    *base,
+		Symbols: make(symbolassert.SymbolMap),
+	}
+	for _, arg := range flag.Args() {
+		remote, local, ok := strings.Cut(arg, "=")
+		if !ok {
+			local = remote
+		}
+		g.Symbols[remote] = local
+	}
+	if *targets != "" {
+		for _, t := range strings.Split(*targets, ",") {
+			goos, goarch, ok := strings.Cut(t, "/")
+			if !ok {
+				fatalf("invalid target: %s", t)
+			}
+			g.Targets = append(g.Targets, symbolassert.Target{GOOS: goos, GOARCH: goarch})
+		}
+	}
+	if *transform != "" {
+		var chain []symbolassert.NameTransform
+		for _, name := range strings.Split(*transform, ",") {
+			fn, ok := transforms[name]
+			if !ok {
+				fatalf("unknown transform: %s", name)
+			}
+			chain = append(chain, fn)
+		}
+		g.Transform = symbolassert.Chain(chain...)
+	}
+
+	if err := g.WriteFiles(*dir); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func transformNames() string {
+	names := make([]string, 0, len(transforms))
+	for name := range transforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "symbolmirror: "+format+"\n", args...)
+	os.Exit(1)
+}
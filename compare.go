@@ -0,0 +1,786 @@
+package symbolassert
+
+import (
+	"fmt"
+	"go/build"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"math"
+	"sort"
+)
+
+type comparer struct {
+	cfg  *Config
+	errb errorsBuilder
+
+	// objects being compared
+	from, to types.Object
+
+	// correspondence between named types, a named type
+	// that is mapped only equals the types it maps to
+	objects ObjectPairs
+	pairs   map[[2]types.Object]bool // aliases resolved
+	known   map[types.Object]bool    // aliases resolved, either side
+
+	// pairs of named types being compared, assumed equal
+	// when visited again so recursive types terminate
+	visiting map[[2]*types.Named]bool
+}
+
+// compare compares the objects, the paths of mismatches
+// are rooted at path.
+func (c *comparer) compare(path string, lhs, rhs types.Object) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*UnsupportedError)
+			if !ok {
+				panic(r)
+			}
+			e.Object = lhs
+			c.errb = append(c.errb, e)
+		}
+	}()
+
+	c.from, c.to = lhs, rhs
+
+	switch lhs := lhs.(type) {
+	case *types.Const:
+		if rhs, ok := rhs.(*types.Const); ok {
+			c.report(c.constDiffs(path, lhs, rhs)...)
+			return
+		}
+
+	case *types.Func:
+		if rhs, ok := rhs.(*types.Func); ok {
+			if !c.equalFunc(lhs, rhs, false) {
+				c.report(&MismatchError{Kind: TypeMismatch, Path: path,
+					FromValue: lhs.Type(), ToValue: rhs.Type()})
+			}
+			if lrecv, rrecv := receiverKind(lhs), receiverKind(rhs); lrecv != rrecv {
+				c.report(&MismatchError{Kind: ReceiverMismatch, Path: path,
+					FromValue: lrecv, ToValue: rrecv})
+			}
+			return
+		}
+
+	case *types.TypeName:
+		if rhs, ok := rhs.(*types.TypeName); ok {
+			if lhs.IsAlias() || rhs.IsAlias() {
+				c.report(c.aliasDiffs(path, lhs, rhs)...)
+				return
+			}
+			c.report(c.typeDiffs(path, lhs.Type(), rhs.Type())...)
+			return
+		}
+
+	case *types.Var:
+		if _, ok := rhs.(*types.Var); ok {
+			c.report(c.typeDiffs(path, lhs.Type(), rhs.Type())...)
+			return
+		}
+
+	default:
+		c.errb = append(c.errb, &UnsupportedError{Object: lhs})
+		return
+	}
+
+	c.report(&MismatchError{Kind: ObjectMismatch, Path: path,
+		FromValue: objectKind(lhs), ToValue: objectKind(rhs)})
+}
+
+// report adds the differences found between the
+// objects being compared.
+func (c *comparer) report(diffs ...*MismatchError) {
+	for _, diff := range diffs {
+		diff.From, diff.To = c.from, c.to
+		c.errb = append(c.errb, diff)
+	}
+}
+
+func objectKind(obj types.Object) string {
+	switch obj.(type) {
+	case *types.Const:
+		return "constant"
+	case *types.Func:
+		return "function"
+	case *types.TypeName:
+		return "type"
+	case *types.Var:
+		return "variable"
+	}
+	return fmt.Sprintf("%T", obj)
+}
+
+func (c *comparer) constDiffs(path string, lhs, rhs *types.Const) []*MismatchError {
+	ltyp, ok := lhs.Type().Underlying().(*types.Basic)
+	if !ok {
+		panic("constant is not basic type (left)")
+	}
+	rtyp, ok := rhs.Type().Underlying().(*types.Basic)
+	if !ok {
+		panic("constant is not basic type (right)")
+	}
+
+	kindMismatch := &MismatchError{Kind: KindMismatch, Path: path,
+		FromValue: lhs.Type(), ToValue: rhs.Type()}
+	untyped := ltyp.Info()&types.IsUntyped != rtyp.Info()&types.IsUntyped
+	if c.cfg.AllowUntyped && untyped {
+		// value of the untyped constant must fit the typed one
+		val, typ := lhs.Val(), rtyp
+		if ltyp.Info()&types.IsUntyped == 0 {
+			val, typ = rhs.Val(), ltyp
+		}
+		if reason := c.representable(val, typ); reason != "" {
+			kindMismatch.Reason = reason
+			return []*MismatchError{kindMismatch}
+		}
+	} else if ltyp.Kind() != rtyp.Kind() {
+		return []*MismatchError{kindMismatch}
+	}
+	if constant.Compare(lhs.Val(), token.NEQ, rhs.Val()) {
+		return []*MismatchError{{Kind: ValueMismatch, Path: path,
+			FromValue: lhs.Val(), ToValue: rhs.Val()}}
+	}
+
+	return nil
+}
+
+// representable reports why val can't be represented
+// exactly by a constant of type typ, if so.
+func (c *comparer) representable(val constant.Value, typ *types.Basic) string {
+	info := typ.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		if val.Kind() == constant.Bool {
+			return ""
+		}
+
+	case info&types.IsString != 0:
+		if val.Kind() == constant.String {
+			return ""
+		}
+
+	case info&types.IsInteger != 0:
+		val := constant.ToInt(val)
+		if val.Kind() != constant.Int {
+			break
+		}
+		bits := uint(8 * c.sizes().Sizeof(typ))
+		min := constant.MakeInt64(0)
+		max := constant.Shift(constant.MakeInt64(1), token.SHL, bits)
+		if info&types.IsUnsigned == 0 {
+			max = constant.Shift(max, token.SHR, 1)
+			min = constant.UnaryOp(token.SUB, max, 0)
+		}
+		if constant.Compare(val, token.LSS, min) ||
+			constant.Compare(val, token.GEQ, max) {
+			return fmt.Sprintf("%v overflows %v", val, typ)
+		}
+		return ""
+
+	case info&types.IsFloat != 0:
+		val := constant.ToFloat(val)
+		if val.Kind() != constant.Float && val.Kind() != constant.Int {
+			break
+		}
+		return representableFloat(val, typ)
+
+	case info&types.IsComplex != 0:
+		val := constant.ToComplex(val)
+		if val.Kind() != constant.Complex {
+			break
+		}
+		part := types.Typ[types.Float64]
+		if typ.Kind() == types.Complex64 {
+			part = types.Typ[types.Float32]
+		}
+		if reason := representableFloat(constant.Real(val), part); reason != "" {
+			return reason
+		}
+		return representableFloat(constant.Imag(val), part)
+	}
+
+	return fmt.Sprintf("%v is not representable by %v", val, typ)
+}
+
+func representableFloat(val constant.Value, typ *types.Basic) string {
+	var (
+		f     float64
+		exact bool
+	)
+	if typ.Kind() == types.Float32 {
+		var f32 float32
+		f32, exact = constant.Float32Val(val)
+		f = float64(f32)
+	} else {
+		f, exact = constant.Float64Val(val)
+	}
+	switch {
+	case math.IsInf(f, 0):
+		return fmt.Sprintf("%v overflows %v", val, typ)
+	case !exact:
+		return fmt.Sprintf("%v is truncated by %v", val, typ)
+	}
+	return ""
+}
+
+func (c *comparer) equalFunc(lfn, rfn *types.Func, hasRecv bool) bool {
+	lsig, ok := lfn.Type().Underlying().(*types.Signature)
+	if !ok {
+		panic("function has no signature (left)")
+	}
+	rsig, ok := rfn.Type().Underlying().(*types.Signature)
+	if !ok {
+		panic("function has no signature (right)")
+	}
+
+	if !c.equalSignature(lsig, rsig) {
+		return false
+	}
+
+	if hasRecv {
+		if lsig.Recv() == nil || rsig.Recv() == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *comparer) equalSignature(lhs, rhs *types.Signature) bool {
+	return c.equalTypeParams(lhs.TypeParams(), rhs.TypeParams()) &&
+		lhs.Variadic() == rhs.Variadic() &&
+		c.equalTuple(lhs.Params(), rhs.Params()) &&
+		c.equalTuple(lhs.Results(), rhs.Results())
+}
+
+func (c *comparer) equalType(lhs, rhs types.Type) bool {
+	lhs, rhs = unalias(lhs), unalias(rhs)
+	switch ltyp := lhs.(type) {
+	case *types.Named:
+		rtyp, ok := rhs.(*types.Named)
+		if !ok {
+			return false
+		}
+		if ltyp.TypeArgs().Len() > 0 || rtyp.TypeArgs().Len() > 0 {
+			// instantiated type
+			return c.equalNamed(ltyp.Origin(), rtyp.Origin()) &&
+				c.equalTypeList(ltyp.TypeArgs(), rtyp.TypeArgs())
+		}
+		return c.equalNamed(ltyp, rtyp)
+
+	case *types.Interface:
+		rtyp, ok := rhs.(*types.Interface)
+		if !ok || ltyp.IsComparable() != rtyp.IsComparable() ||
+			ltyp.IsMethodSet() != rtyp.IsMethodSet() {
+			return false
+		}
+		if !ltyp.IsMethodSet() {
+			// constraint interface, compare type terms
+			embeddeds := ltyp.NumEmbeddeds()
+			if embeddeds != rtyp.NumEmbeddeds() {
+				return false
+			}
+			for i := 0; i < embeddeds; i++ {
+				if !c.equalType(ltyp.EmbeddedType(i), rtyp.EmbeddedType(i)) {
+					return false
+				}
+			}
+		}
+		return c.equalMethods(ltyp, rtyp)
+
+	case *types.Union:
+		terms := ltyp.Len()
+		rtyp, ok := rhs.(*types.Union)
+		if !ok || terms != rtyp.Len() {
+			return false
+		}
+		for i := 0; i < terms; i++ {
+			lterm, rterm := ltyp.Term(i), rtyp.Term(i)
+			if lterm.Tilde() != rterm.Tilde() ||
+				!c.equalType(lterm.Type(), rterm.Type()) {
+				return false
+			}
+		}
+
+	case *types.TypeParam:
+		// constraints are compared by equalTypeParams
+		rtyp, ok := rhs.(*types.TypeParam)
+		return ok && ltyp.Index() == rtyp.Index()
+
+	case *types.Basic:
+		rtyp, ok := rhs.(*types.Basic)
+		return ok && ltyp.Kind() == rtyp.Kind()
+
+	case *types.Struct:
+		rtyp, ok := rhs.(*types.Struct)
+		return ok && len(c.structDiffs("", ltyp, rtyp)) == 0
+
+	case *types.Pointer:
+		rtyp, ok := rhs.(*types.Pointer)
+		return ok && c.equalType(ltyp.Elem(), rtyp.Elem())
+
+	case *types.Slice:
+		rtyp, ok := rhs.(*types.Slice)
+		return ok && c.equalType(ltyp.Elem(), rtyp.Elem())
+
+	case *types.Array:
+		rtyp, ok := rhs.(*types.Array)
+		return ok && ltyp.Len() == rtyp.Len() &&
+			c.equalType(ltyp.Elem(), rtyp.Elem())
+
+	case *types.Map:
+		rtyp, ok := rhs.(*types.Map)
+		return ok && c.equalType(ltyp.Key(), rtyp.Key()) &&
+			c.equalType(ltyp.Elem(), rtyp.Elem())
+
+	case *types.Chan:
+		rtyp, ok := rhs.(*types.Chan)
+		return ok && ltyp.Dir() == rtyp.Dir() &&
+			c.equalType(ltyp.Elem(), rtyp.Elem())
+
+	case *types.Signature:
+		rtyp, ok := rhs.(*types.Signature)
+		return ok && c.equalSignature(ltyp, rtyp)
+
+	default:
+		panic(&UnsupportedError{Type: lhs})
+	}
+
+	return true
+}
+
+// aliasDiffs compares type names of which at least one is
+// an alias. The types they denote are compared, unless
+// strict aliases are configured.
+func (c *comparer) aliasDiffs(path string, lhs, rhs *types.TypeName) []*MismatchError {
+	ltarget, rtarget := unalias(lhs.Type()), unalias(rhs.Type())
+	sameTarget := identical(ltarget, rtarget)
+	if c.cfg.StrictAliases {
+		if !lhs.IsAlias() || !rhs.IsAlias() {
+			return []*MismatchError{{Kind: AliasMismatch, Path: path,
+				FromValue: ltarget, ToValue: rtarget, Reason: "not an alias"}}
+		}
+		if !sameTarget {
+			return []*MismatchError{{Kind: AliasMismatch, Path: path,
+				FromValue: ltarget, ToValue: rtarget}}
+		}
+	}
+	if sameTarget {
+		return nil
+	}
+	return c.typeDiffs(path, ltarget, rtarget)
+}
+
+// typeDiffs returns each difference between lhs and rhs.
+// The path locates the types within the objects compared.
+func (c *comparer) typeDiffs(path string, lhs, rhs types.Type) []*MismatchError {
+	lhs, rhs = unalias(lhs), unalias(rhs)
+	if c.cfg.Layout {
+		return c.layoutDiffs(path, lhs, rhs)
+	}
+
+	ln, lok := lhs.(*types.Named)
+	rn, rok := rhs.(*types.Named)
+	if lok && rok && ln.TypeArgs().Len() == 0 && rn.TypeArgs().Len() == 0 {
+		top := ln.Obj() == resolveAlias(c.from) && rn.Obj() == resolveAlias(c.to)
+		if equal, known := c.correspond(ln.Obj(), rn.Obj()); known && !top {
+			if !equal {
+				return []*MismatchError{{Kind: TypeMismatch, Path: path,
+					FromValue: lhs, ToValue: rhs}}
+			}
+			return nil
+		}
+		if !c.equalTypeParams(ln.TypeParams(), rn.TypeParams()) {
+			return []*MismatchError{{Kind: TypeMismatch, Path: path,
+				FromValue: lhs, ToValue: rhs}}
+		}
+		diffs := c.typeDiffs(path, ln.Underlying(), rn.Underlying())
+		return append(diffs, c.methodDiffs(path, ln, rn)...)
+	}
+
+	lst, lok := lhs.(*types.Struct)
+	rst, rok := rhs.(*types.Struct)
+	if lok && rok {
+		return c.structDiffs(path, lst, rst)
+	}
+
+	if !c.equalType(lhs, rhs) {
+		return []*MismatchError{{Kind: TypeMismatch, Path: path,
+			FromValue: lhs, ToValue: rhs}}
+	}
+	return nil
+}
+
+// correspond reports whether the named types declared by
+// lhs and rhs are equal according to the correspondence
+// table. It is unknown if neither type is mapped.
+func (c *comparer) correspond(lhs, rhs types.Object) (equal, known bool) {
+	if sameObject(lhs, rhs) {
+		return true, true
+	}
+	if c.pairs == nil {
+		c.pairs = make(map[[2]types.Object]bool, len(c.objects))
+		c.known = make(map[types.Object]bool, 2*len(c.objects))
+		for _, p := range c.objects {
+			from, to := resolveAlias(p.From), resolveAlias(p.To)
+			c.pairs[[2]types.Object{from, to}] = true
+			c.known[from] = true
+			c.known[to] = true
+		}
+	}
+	if c.pairs[[2]types.Object{lhs, rhs}] {
+		return true, true
+	}
+	if c.known[lhs] || c.known[rhs] {
+		// corresponds to another type
+		return false, true
+	}
+	return false, false
+}
+
+// sameObject reports whether lhs and rhs are the same
+// package-level object. The packages compared are loaded
+// separately, so a package they both import is loaded twice.
+func sameObject(lhs, rhs types.Object) bool {
+	if lhs == rhs {
+		return true
+	}
+	if lhs.Pkg() == nil || rhs.Pkg() == nil ||
+		lhs.Parent() != lhs.Pkg().Scope() || rhs.Parent() != rhs.Pkg().Scope() {
+		return false
+	}
+	return lhs.Pkg().Path() == rhs.Pkg().Path() && lhs.Name() == rhs.Name()
+}
+
+// identical reports whether lhs and rhs are identical types,
+// named types are identical if declared by the same object.
+func identical(lhs, rhs types.Type) bool {
+	ln, lok := lhs.(*types.Named)
+	rn, rok := rhs.(*types.Named)
+	if lok && rok && ln.TypeArgs().Len() == 0 && rn.TypeArgs().Len() == 0 {
+		return sameObject(ln.Obj(), rn.Obj())
+	}
+	return types.Identical(lhs, rhs)
+}
+
+// mapped reports whether lhs and rhs are named types
+// whose equality follows from the correspondence table.
+func (c *comparer) mapped(lhs, rhs types.Type) bool {
+	ln, lok := lhs.(*types.Named)
+	rn, rok := rhs.(*types.Named)
+	if !lok || !rok {
+		return false
+	}
+	_, known := c.correspond(ln.Origin().Obj(), rn.Origin().Obj())
+	return known
+}
+
+// equalNamed compares named types structurally, unless
+// their equality follows from the correspondence table.
+func (c *comparer) equalNamed(lhs, rhs *types.Named) bool {
+	if equal, known := c.correspond(lhs.Obj(), rhs.Obj()); known {
+		return equal
+	}
+
+	key := [2]*types.Named{lhs, rhs}
+	if c.visiting[key] {
+		return true
+	}
+	if c.visiting == nil {
+		c.visiting = make(map[[2]*types.Named]bool)
+	}
+	c.visiting[key] = true
+	defer delete(c.visiting, key)
+
+	return c.equalType(lhs.Underlying(), rhs.Underlying()) &&
+		c.equalTypeParams(lhs.TypeParams(), rhs.TypeParams()) &&
+		c.equalMethods(lhs, rhs)
+}
+
+// structDiffs returns each difference between
+// the fields of lhs and rhs.
+func (c *comparer) structDiffs(path string, lhs, rhs *types.Struct) (diffs []*MismatchError) {
+	if c.cfg.IgnoreFieldNames {
+		// fields are matched by index
+		fields := lhs.NumFields()
+		if fields != rhs.NumFields() {
+			return []*MismatchError{{Kind: FieldCountMismatch, Path: path,
+				FromValue: fields, ToValue: rhs.NumFields()}}
+		}
+		for i := 0; i < fields; i++ {
+			diffs = append(diffs, c.fieldDiffs(path, lhs, rhs, i, i)...)
+		}
+		return diffs
+	}
+
+	// fields are matched by name
+	matched := make([]bool, rhs.NumFields())
+	var missing []int
+	for i := 0; i < lhs.NumFields(); i++ {
+		name := lhs.Field(i).Name()
+		j := fieldIndex(rhs, name)
+		if j == -1 {
+			missing = append(missing, i)
+			continue
+		}
+		matched[j] = true
+		diffs = append(diffs, c.fieldDiffs(path, lhs, rhs, i, j)...)
+		if !c.cfg.IgnoreFieldOrder && i != j {
+			diffs = append(diffs, &MismatchError{Kind: FieldOrderMismatch,
+				Path: path + "." + name, ToField: name, FromValue: i, ToValue: j})
+		}
+	}
+	for _, i := range missing {
+		name := lhs.Field(i).Name()
+		if i < len(matched) && !matched[i] {
+			// field at same position is renamed
+			matched[i] = true
+			diffs = append(diffs, &MismatchError{Kind: FieldNameMismatch,
+				Path: path + "." + name, ToField: rhs.Field(i).Name(),
+				FromValue: name, ToValue: rhs.Field(i).Name()})
+			diffs = append(diffs, c.fieldDiffs(path, lhs, rhs, i, i)...)
+			continue
+		}
+		diffs = append(diffs, &MismatchError{Kind: MissingField, Path: path + "." + name})
+	}
+	for j, ok := range matched {
+		if !ok {
+			diffs = append(diffs, &MismatchError{Kind: ExtraField,
+				Path: path + "." + rhs.Field(j).Name(), ToField: rhs.Field(j).Name()})
+		}
+	}
+	return diffs
+}
+
+// fieldDiffs compares field i of lhs with field j of rhs.
+func (c *comparer) fieldDiffs(path string, lhs, rhs *types.Struct, i, j int) (diffs []*MismatchError) {
+	lf, rf := lhs.Field(i), rhs.Field(j)
+	path += "." + lf.Name()
+
+	_, lok := lf.Type().Underlying().(*types.Struct)
+	_, rok := rf.Type().Underlying().(*types.Struct)
+	if lok && rok && !c.mapped(lf.Type(), rf.Type()) {
+		// locate the difference within the struct
+		diffs = c.typeDiffs(path, lf.Type(), rf.Type())
+	} else if !c.equalType(lf.Type(), rf.Type()) {
+		diffs = append(diffs, &MismatchError{Kind: FieldTypeMismatch, Path: path,
+			ToField: rf.Name(), FromValue: lf.Type(), ToValue: rf.Type()})
+	}
+	if !c.cfg.IgnoreEmbedding && lf.Embedded() != rf.Embedded() {
+		diffs = append(diffs, &MismatchError{Kind: EmbeddingMismatch, Path: path,
+			ToField: rf.Name(), FromValue: lf.Embedded(), ToValue: rf.Embedded()})
+	}
+	if !c.cfg.IgnoreFieldTags && lhs.Tag(i) != rhs.Tag(j) {
+		diffs = append(diffs, &MismatchError{Kind: TagMismatch, Path: path,
+			ToField: rf.Name(), FromValue: lhs.Tag(i), ToValue: rhs.Tag(j)})
+	}
+	return diffs
+}
+
+func fieldIndex(s *types.Struct, name string) int {
+	for i := 0; i < s.NumFields(); i++ {
+		if s.Field(i).Name() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// layoutDiffs returns each difference in memory layout
+// between lhs and rhs. Struct fields are matched by
+// position, their names are ignored.
+func (c *comparer) layoutDiffs(path string, lhs, rhs types.Type) (diffs []*MismatchError) {
+	lhs, rhs = unalias(lhs), unalias(rhs)
+	for _, typ := range []types.Type{lhs, rhs} {
+		if n, ok := typ.(*types.Named); ok && n.TypeParams().Len() > 0 && n.TypeArgs().Len() == 0 {
+			// layout of uninstantiated generic type is undefined
+			panic(&UnsupportedError{Type: typ})
+		}
+	}
+
+	sizes := c.sizes()
+	if ls, rs := sizes.Sizeof(lhs), sizes.Sizeof(rhs); ls != rs {
+		diffs = append(diffs, &MismatchError{Kind: SizeMismatch, Path: path,
+			FromValue: ls, ToValue: rs})
+	}
+	if la, ra := sizes.Alignof(lhs), sizes.Alignof(rhs); la != ra {
+		diffs = append(diffs, &MismatchError{Kind: AlignmentMismatch, Path: path,
+			FromValue: la, ToValue: ra})
+	}
+
+	lst, lok := lhs.Underlying().(*types.Struct)
+	rst, rok := rhs.Underlying().(*types.Struct)
+	if !lok || !rok {
+		return diffs
+	}
+	lfields, rfields := structFields(lst), structFields(rst)
+	if len(lfields) != len(rfields) {
+		return append(diffs, &MismatchError{Kind: FieldCountMismatch, Path: path,
+			FromValue: len(lfields), ToValue: len(rfields)})
+	}
+	loffs, roffs := sizes.Offsetsof(lfields), sizes.Offsetsof(rfields)
+	for i, lf := range lfields {
+		path := path + "." + lf.Name()
+		if loffs[i] != roffs[i] {
+			diffs = append(diffs, &MismatchError{Kind: OffsetMismatch, Path: path,
+				ToField: rfields[i].Name(), FromValue: loffs[i], ToValue: roffs[i]})
+		}
+		diffs = append(diffs, c.layoutDiffs(path, lf.Type(), rfields[i].Type())...)
+	}
+	return diffs
+}
+
+func (c *comparer) sizes() types.Sizes {
+	if c.cfg.Sizes != nil {
+		return c.cfg.Sizes
+	}
+	return types.SizesFor("gc", build.Default.GOARCH)
+}
+
+func structFields(s *types.Struct) []*types.Var {
+	fields := make([]*types.Var, s.NumFields())
+	for i := range fields {
+		fields[i] = s.Field(i)
+	}
+	return fields
+}
+
+func (c *comparer) equalTuple(lhs, rhs *types.Tuple) bool {
+	n := lhs.Len()
+	if n != rhs.Len() {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if !c.equalType(lhs.At(i).Type(), rhs.At(i).Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *comparer) equalTypeList(lhs, rhs *types.TypeList) bool {
+	n := lhs.Len()
+	if n != rhs.Len() {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if !c.equalType(lhs.At(i), rhs.At(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *comparer) equalTypeParams(lhs, rhs *types.TypeParamList) bool {
+	n := lhs.Len()
+	if n != rhs.Len() {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		// compare the type set, a constraint may be an alias
+		lcon := lhs.At(i).Constraint().Underlying()
+		rcon := rhs.At(i).Constraint().Underlying()
+		if !c.equalType(lcon, rcon) {
+			return false
+		}
+	}
+	return true
+}
+
+// methodDiffs returns each difference between the method
+// sets of lhs and rhs. Methods are matched by name and
+// include methods promoted from embedded fields.
+func (c *comparer) methodDiffs(path string, lhs, rhs types.Type) (diffs []*MismatchError) {
+	lset, rset := methodSet(lhs), methodSet(rhs)
+	for _, name := range methodNames(lset, rset) {
+		lm, lok := lset[name]
+		rm, rok := rset[name]
+		path := path + "." + name
+		switch {
+		case !rok:
+			diffs = append(diffs, &MismatchError{Kind: MissingMethod, Path: path})
+		case !lok:
+			diffs = append(diffs, &MismatchError{Kind: ExtraMethod, Path: path})
+		default:
+			if !c.equalFunc(lm.fn, rm.fn, true) {
+				diffs = append(diffs, &MismatchError{Kind: MethodMismatch, Path: path,
+					FromValue: lm.fn.Type(), ToValue: rm.fn.Type()})
+			}
+			if lm.ptr != rm.ptr {
+				diffs = append(diffs, &MismatchError{Kind: ReceiverMismatch, Path: path,
+					FromValue: lm.receiver(), ToValue: rm.receiver()})
+			}
+		}
+	}
+	return diffs
+}
+
+func (c *comparer) equalMethods(lhs, rhs types.Type) bool {
+	return len(c.methodDiffs("", lhs, rhs)) == 0
+}
+
+type method struct {
+	fn  *types.Func
+	ptr bool // only in method set of pointer type
+}
+
+func (m method) receiver() string {
+	if m.ptr {
+		return "pointer"
+	}
+	return "value"
+}
+
+// receiverKind returns the kind of receiver of a method
+// like method.receiver, or "" for a function.
+func receiverKind(fn *types.Func) string {
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return ""
+	}
+	if _, ok := recv.Type().(*types.Pointer); ok {
+		return "pointer"
+	}
+	return "value"
+}
+
+// methodSet returns the methods callable on a value of type
+// typ or a pointer to it, by name.
+func methodSet(typ types.Type) map[string]method {
+	if types.IsInterface(typ) {
+		mset := types.NewMethodSet(typ)
+		methods := make(map[string]method, mset.Len())
+		for i := 0; i < mset.Len(); i++ {
+			fn := mset.At(i).Obj().(*types.Func)
+			methods[fn.Name()] = method{fn: fn}
+		}
+		return methods
+	}
+
+	var value stringSet
+	vset := types.NewMethodSet(typ)
+	for i := 0; i < vset.Len(); i++ {
+		value.Add(vset.At(i).Obj().Name())
+	}
+	pset := types.NewMethodSet(types.NewPointer(typ))
+	methods := make(map[string]method, pset.Len())
+	for i := 0; i < pset.Len(); i++ {
+		fn := pset.At(i).Obj().(*types.Func)
+		methods[fn.Name()] = method{fn: fn, ptr: !value.Contains(fn.Name())}
+	}
+	return methods
+}
+
+func methodNames(sets ...map[string]method) []string {
+	var names stringSet
+	for _, set := range sets {
+		for name := range set {
+			names.Add(name)
+		}
+	}
+	entries := names.entries()
+	sort.Strings(entries)
+	return entries
+}
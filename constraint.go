@@ -0,0 +1,70 @@
+package symbolassert
+
+import (
+	"fmt"
+	"go/build"
+	"go/build/constraint"
+	"strings"
+)
+
+// unixOS is the set of GOOS values matched by the unix tag.
+var unixOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true,
+	"freebsd": true, "hurd": true, "illumos": true, "ios": true,
+	"linux": true, "netbsd": true, "openbsd": true, "solaris": true,
+}
+
+// matchTag reports whether a build tag is satisfied when
+// building for goos and goarch with the given tags. Empty
+// values default to the host.
+func matchTag(goos, goarch string, tags []string, tag string) bool {
+	if goos == "" {
+		goos = build.Default.GOOS
+	}
+	if goarch == "" {
+		goarch = build.Default.GOARCH
+	}
+	switch tag {
+	case goos, goarch:
+		return true
+	case "unix":
+		return unixOS[goos]
+	case "linux":
+		return goos == "android"
+	case "darwin":
+		return goos == "ios"
+	case "solaris":
+		return goos == "illumos"
+	}
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	for _, t := range build.Default.ReleaseTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// matchConstraint reports whether the build constraint
+// expression expr, like "linux && !386", is satisfied by
+// the platform of p. An empty expression always is.
+func matchConstraint(expr string, p Provider) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+	x, err := constraint.Parse("//go:build " + expr)
+	if err != nil {
+		return false, fmt.Errorf("invalid constraint %q: %w", expr, err)
+	}
+	l, ok := p.(locator)
+	if !ok {
+		return x.Eval(func(tag string) bool {
+			return matchTag("", "", nil, tag)
+		}), nil
+	}
+	return x.Eval(l.matchTag), nil
+}
@@ -0,0 +1,87 @@
+package symbolassert
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"path"
+	"strings"
+)
+
+// Coverage reports which authoritative symbols of a
+// namespace are mapped by a SymbolMap.
+type Coverage struct {
+	// Namespace is a symbol pattern in the syntax of
+	// path.Match, like "unix.SIOC*".
+	Namespace string
+
+	// Mapped and Unmapped hold the exported symbols in the
+	// namespace that are covered by an entry or not, sorted
+	// and qualified like Namespace.
+	Mapped   []string
+	Unmapped []string
+}
+
+// Err returns an *UnmappedError if symbols are unmapped,
+// so a test can fail if the authoritative package adds
+// symbols to the namespace.
+func (c *Coverage) Err() error {
+	if len(c.Unmapped) == 0 {
+		return nil
+	}
+	return &UnmappedError{Namespace: c.Namespace, Symbols: c.Unmapped}
+}
+
+// UnmappedError is returned from Coverage.Err.
+type UnmappedError struct {
+	Namespace string
+	Symbols   []string
+}
+
+func (e *UnmappedError) Error() string {
+	if len(e.Symbols) == 1 {
+		return fmt.Sprintf("unmapped symbol: %s", e.Symbols[0])
+	}
+	return fmt.Sprintf("%d unmapped symbols in %s: %s",
+		len(e.Symbols), e.Namespace, strings.Join(e.Symbols, ", "))
+}
+
+// Coverage lists the symbols of from in namespace that the
+// symbol map covers, with its patterns expanded. The
+// provider must implement Lister.
+func (m SymbolMap) Coverage(from Provider, namespace string) (*Coverage, error) {
+	sel, err := parseSymbol(namespace)
+	lister, ok := from.(Lister)
+	if err != nil || !ok || len(sel.names) != 1 {
+		return nil, &PatternError{Pattern: namespace, Err: ErrInvalidPattern}
+	}
+	name := sel.names[0]
+	if _, err := path.Match(name, ""); err != nil {
+		return nil, &PatternError{Pattern: namespace, Err: ErrInvalidPattern}
+	}
+	qualifier := namespace[:len(namespace)-len(name)]
+
+	expanded, _, errb := m.expand(from, nil)
+	if err := errb.Build(); err != nil {
+		return nil, err
+	}
+	mapped := make(map[types.Object]bool, len(expanded))
+	for remote := range expanded {
+		if obj := from.Lookup(remote); obj != nil {
+			mapped[obj] = true
+		}
+	}
+
+	c := &Coverage{Namespace: namespace}
+	for _, sym := range lister.Symbols(sel.pkg) {
+		if ok, _ := path.Match(name, sym); !ok || !token.IsExported(sym) {
+			continue
+		}
+		if mapped[from.Lookup(qualifier+sym)] {
+			c.Mapped = append(c.Mapped, qualifier+sym)
+		} else {
+			c.Unmapped = append(c.Unmapped, qualifier+sym)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,119 @@
+package symbolassert
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// DeriveOptions configures DeriveSymbolMap.
+type DeriveOptions struct {
+	// FromPackage and ToPackage select the package that
+	// is listed, like Lister.Symbols. The derived entries
+	// are qualified by them if not empty.
+	FromPackage, ToPackage string
+
+	// Include and Exclude filter the derived names with
+	// patterns in the syntax of path.Match. A name is
+	// derived if it matches any pattern of Include, or if
+	// Include is empty, and no pattern of Exclude.
+	Include []string
+	Exclude []string
+
+	// Transform derives the local name from the
+	// authoritative one. The name is kept if nil.
+	Transform NameTransform
+}
+
+// DeriveSymbolMap returns a SymbolMap that maps every
+// exported symbol of the authoritative package to the
+// symbol of the local package with the same name, or the
+// name given by the transform, if it exists. The filters
+// match the authoritative name.
+// Both providers must implement Lister and be loaded.
+// The options may be nil.
+func DeriveSymbolMap(from, to Provider, opts *DeriveOptions) (SymbolMap, error) {
+	if opts == nil {
+		opts = &DeriveOptions{}
+	}
+	fromLister, ok := from.(Lister)
+	if !ok {
+		return nil, fmt.Errorf("provider can't list symbols: %T", from)
+	}
+	toLister, ok := to.(Lister)
+	if !ok {
+		return nil, fmt.Errorf("provider can't list symbols: %T", to)
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	var local stringSet
+	for _, name := range toLister.Symbols(opts.ToPackage) {
+		local.Add(name)
+	}
+
+	m := make(SymbolMap)
+	for _, name := range fromLister.Symbols(opts.FromPackage) {
+		if !token.IsExported(name) || !opts.match(name) {
+			continue
+		}
+		target := name
+		if opts.Transform != nil {
+			target = opts.Transform(name)
+		}
+		if local.Contains(target) {
+			m[qualify(opts.FromPackage, name)] = qualify(opts.ToPackage, target)
+		}
+	}
+	return m, nil
+}
+
+func (o *DeriveOptions) validate() error {
+	for _, pattern := range o.Include {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("include %s: %w", pattern, err)
+		}
+	}
+	for _, pattern := range o.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("exclude %s: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// match reports whether name passes the filters,
+// which must be valid.
+func (o *DeriveOptions) match(name string) bool {
+	included := len(o.Include) == 0
+	for _, pattern := range o.Include {
+		if ok, _ := path.Match(pattern, name); ok {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, pattern := range o.Exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// qualify returns the symbol for name in package pkg,
+// with pkg quoted if its last element has a dot.
+func qualify(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	if strings.Contains(pkg[strings.LastIndexByte(pkg, '/')+1:], ".") {
+		pkg = strconv.Quote(pkg)
+	}
+	return pkg + "." + name
+}
@@ -0,0 +1,189 @@
+package symbolassert
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around
+// the changes of a hunk.
+const diffContext = 3
+
+// writeUnifiedDiff writes the difference between the old
+// and new content of a file in the unified format.
+func writeUnifiedDiff(b *bytes.Buffer, name, old, new string) {
+	a, z := splitLines(old), splitLines(new)
+	ops := diffLines(a, z)
+	if len(ops) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "--- %s\n+++ %s\n", name, name)
+
+	// line numbers before each op
+	ai, zi := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for i, op := range ops {
+		ai[i+1], zi[i+1] = ai[i], zi[i]
+		if op.kind != '+' {
+			ai[i+1]++
+		}
+		if op.kind != '-' {
+			zi[i+1]++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// hunk from the context before change i to the context
+		// after the last change that is close enough
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			} else if j-end >= 2*diffContext {
+				break
+			}
+		}
+		stop := end + diffContext
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		fmt.Fprintf(b, "@@ -%s +%s @@\n",
+			hunkRange(ai[start], ai[stop]), hunkRange(zi[start], zi[stop]))
+		for _, op := range ops[start:stop] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = stop
+	}
+}
+
+// hunkRange formats the lines [from, to) of a hunk,
+// starting at 1.
+func hunkRange(from, to int) string {
+	if n := to - from; n != 1 {
+		if n == 0 {
+			return fmt.Sprintf("%d,0", from)
+		}
+		return fmt.Sprintf("%d,%d", from+1, n)
+	}
+	return fmt.Sprint(from + 1)
+}
+
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// diffLines returns the shortest edit script from a to z,
+// or nil if they are equal. It uses the algorithm of Myers.
+func diffLines(a, z []string) []diffOp {
+	// common prefix and suffix
+	prefix := 0
+	for prefix < len(a) && prefix < len(z) && a[prefix] == z[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(z)-prefix &&
+		a[len(a)-1-suffix] == z[len(z)-1-suffix] {
+		suffix++
+	}
+	if prefix == len(a) && prefix == len(z) {
+		return nil
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, myers(a[prefix:len(a)-suffix], z[prefix:len(z)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+func myers(a, z []string) []diffOp {
+	n, m := len(a), len(z)
+	max := n + m
+	off := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+	var d int
+search:
+	for d = 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[off+k-1] < v[off+k+1] {
+				x = v[off+k+1] // insertion
+			} else {
+				x = v[off+k-1] + 1 // deletion
+			}
+			y := x - k
+			for x < n && y < m && a[x] == z[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	var ops []diffOp
+	x, y := n, m
+	for ; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prev int
+		if k == -d || k != d && v[off+k-1] < v[off+k+1] {
+			prev = k + 1
+		} else {
+			prev = k - 1
+		}
+		px := v[off+prev]
+		py := px - prev
+		for x > px && y > py {
+			x--
+			y--
+			ops = append(ops, diffOp{' ', a[x]})
+		}
+		if x == px {
+			y--
+			ops = append(ops, diffOp{'+', z[y]})
+		} else {
+			x--
+			ops = append(ops, diffOp{'-', a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		ops = append(ops, diffOp{' ', a[x]})
+	}
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
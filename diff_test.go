@@ -0,0 +1,65 @@
+package symbolassert
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_writeUnifiedDiff(t *testing.T) {
+	lines := func(s ...string) string {
+		return strings.Join(s, "\n") + "\n"
+	}
+	cases := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{"Equal", lines("a", "b"), lines("a", "b"), ""},
+		{"Replace", lines("a", "b", "c"), lines("a", "x", "c"), `--- f
++++ f
+@@ -1,3 +1,3 @@
+ a
+-b
++x
+ c
+`},
+		{"Insert", lines("a"), lines("a", "b"), `--- f
++++ f
+@@ -1 +1,2 @@
+ a
++b
+`},
+		{"Delete", lines("a", "b"), lines("b"), `--- f
++++ f
+@@ -1,2 +1 @@
+-a
+ b
+`},
+		{"Hunks", lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10"),
+			lines("x", "2", "3", "4", "5", "6", "7", "8", "9", "y"), `--- f
++++ f
+@@ -1,4 +1,4 @@
+-1
++x
+ 2
+ 3
+ 4
+@@ -7,4 +7,4 @@
+ 7
+ 8
+ 9
+-10
++y
+`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var b bytes.Buffer
+			writeUnifiedDiff(&b, "f", c.old, c.new)
+			if got := b.String(); got != c.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, c.want)
+			}
+		})
+	}
+}
@@ -26,7 +26,7 @@ type fileProvider struct {
 	scope    *types.Scope
 }
 
-var _ Provider = (*fileProvider)(nil)
+var _ Lister = (*fileProvider)(nil)
 
 // FileProvider returns a Provider that resolves symbols
 // based on a set of Go source files.
@@ -85,14 +85,43 @@ func (p *fileProvider) Load(path string) error {
 }
 
 func (p *fileProvider) Lookup(symbol string) types.Object {
-	pkg, name := splitAtLastDot(symbol)
+	return lookupSymbol(symbol, func(pkg string) *types.Scope {
+		if !p.isPkg(pkg) {
+			return nil
+		}
+		return p.scope
+	})
+}
+
+func (p *fileProvider) Symbols(pkg string) []string {
+	if !p.isPkg(pkg) {
+		return nil
+	}
+	return p.scope.Names()
+}
+
+func (p *fileProvider) packagePath(pkg string) string {
+	if !p.isPkg(pkg) {
+		return pkg
+	}
+	return p.pkgPath
+}
+
+// platform is empty, the files are used as given.
+func (p *fileProvider) platform() string {
+	return ""
+}
+
+// matchTag matches the host, the files are used as given.
+func (p *fileProvider) matchTag(tag string) bool {
+	return matchTag("", "", nil, tag)
+}
+
+func (p *fileProvider) isPkg(pkg string) bool {
 	if pkg == "" {
 		pkg = p.pkgName
 	}
-	if !p.pkgPaths.Contains(pkg) {
-		return nil
-	}
-	return p.scope.Lookup(name)
+	return p.pkgPaths.Contains(pkg)
 }
 
 func (p *fileProvider) loadScope() error {
@@ -0,0 +1,392 @@
+package symbolassert
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Fix rewrites a local declaration that drifted from the
+// authoritative one, see Report.Fixes.
+type Fix struct {
+	Err *MismatchError // mismatch that is fixed
+
+	// Pos and End locate the source that is replaced,
+	// Old is the source and New replaces it.
+	Pos, End token.Position
+	Old, New string
+}
+
+func (f *Fix) String() string {
+	return fmt.Sprintf("%v: %s -> %s", f.Pos, f.Old, f.New)
+}
+
+// Fixes returns the fixes of the mismatches in the report,
+// ordered by position. The local provider must have loaded
+// the local package with its syntax, like a PackageProvider
+// with KeepSyntax. These mismatches are fixed:
+//
+//   - the value of a constant, or its type and value if the
+//     type differs
+//   - the type of a variable that is declared with a type
+//   - the underlying type of a type that isn't generic
+//   - the type and tag of a struct field of a declared type
+//
+// Only a declaration of a single name is fixed. A type that
+// refers to a named type of the authoritative package is
+// written as the local type it's mapped to by a pair of the
+// report, it isn't fixed if there is none. Other mismatches,
+// like function signatures and missing fields, are left out.
+func (r *Report) Fixes(to SyntaxProvider) ([]*Fix, error) {
+	fx := &fixer{to: to, sources: make(map[string][]byte)}
+	fx.names = make(map[types.Object]types.Object)
+	for _, p := range r.Pairs {
+		if _, ok := p.To.(*types.TypeName); ok {
+			fx.names[resolveAlias(p.From)] = p.To
+		}
+	}
+
+	var fixes []*Fix
+	seen := make(map[string]bool)
+	for _, err := range reportErrors(r) {
+		e, ok := err.(*MismatchError)
+		if !ok || e.From == nil || e.To == nil {
+			continue
+		}
+		fix, err := fx.fix(e)
+		if err != nil {
+			return nil, err
+		}
+		if fix == nil {
+			continue
+		}
+		if key := fix.Pos.String(); !seen[key] {
+			seen[key] = true
+			fixes = append(fixes, fix)
+		}
+	}
+	sort.SliceStable(fixes, func(i, j int) bool {
+		if fixes[i].Pos.Filename != fixes[j].Pos.Filename {
+			return fixes[i].Pos.Filename < fixes[j].Pos.Filename
+		}
+		return fixes[i].Pos.Offset < fixes[j].Pos.Offset
+	})
+	return fixes, nil
+}
+
+type fixer struct {
+	to      SyntaxProvider
+	names   map[types.Object]types.Object // authoritative to local types
+	sources map[string][]byte             // by file name
+
+	// file being fixed
+	file *ast.File
+	fset *token.FileSet
+}
+
+// fix returns the fix of a mismatch, nil if it can't be fixed.
+func (fx *fixer) fix(e *MismatchError) (*Fix, error) {
+	fx.file, fx.fset = fx.to.Syntax(e.To)
+	if fx.file == nil {
+		return nil, nil
+	}
+	d := &declarer{from: e.From.Pkg(), names: make(map[types.Object]string)}
+	for from, to := range fx.names {
+		if to.Pkg() == e.To.Pkg() {
+			d.names[from] = to.Name()
+		}
+	}
+
+	var start, end token.Pos
+	var repl string
+	switch to := e.To.(type) {
+	case *types.Const:
+		from, ok := e.From.(*types.Const)
+		spec, i := fx.valueSpec(to)
+		if !ok || spec == nil || len(spec.Values) != len(spec.Names) {
+			return nil, nil
+		}
+		switch e.Kind {
+		case ValueMismatch:
+			start, end = spec.Values[i].Pos(), spec.Values[i].End()
+			repl = constLiteral(from.Val(), from.Type())
+		case KindMismatch:
+			if len(spec.Names) != 1 {
+				return nil, nil
+			}
+			start, end = spec.Names[0].End(), spec.Values[0].End()
+			if !isUntyped(from.Type()) {
+				repl = " " + d.typeString(from.Type())
+			}
+			repl += " = " + constLiteral(from.Val(), from.Type())
+		default:
+			return nil, nil
+		}
+
+	case *types.Var:
+		spec, _ := fx.valueSpec(to)
+		if e.Kind != TypeMismatch || e.Path != e.From.Name() ||
+			spec == nil || spec.Type == nil || len(spec.Names) != 1 {
+			return nil, nil
+		}
+		start, end = spec.Type.Pos(), spec.Type.End()
+		repl = d.typeString(e.From.Type())
+
+	case *types.TypeName:
+		spec := fx.typeSpec(to)
+		if spec == nil || spec.Assign.IsValid() || spec.TypeParams != nil && spec.TypeParams.NumFields() > 0 {
+			return nil, nil
+		}
+		switch {
+		case e.Kind == TypeMismatch && e.Path == e.From.Name():
+			if _, ok := e.FromValue.(*types.Named); ok {
+				// not a difference of the underlying type
+				return nil, nil
+			}
+			start, end = spec.Type.Pos(), spec.Type.End()
+			repl = d.underlying(e.From.Type().Underlying())
+		case e.Kind == FieldTypeMismatch || e.Kind == TagMismatch:
+			field := structField(spec, e)
+			if field == nil {
+				return nil, nil
+			}
+			if e.Kind == FieldTypeMismatch {
+				typ, ok := e.FromValue.(types.Type)
+				if !ok {
+					return nil, nil
+				}
+				start, end = field.Type.Pos(), field.Type.End()
+				repl = d.typeString(typ)
+				break
+			}
+			tag, _ := e.FromValue.(string)
+			start, end = field.Type.End(), field.Type.End()
+			if field.Tag != nil {
+				end = field.Tag.End()
+			}
+			if tag != "" {
+				repl = " " + tagLiteral(tag)
+			}
+		default:
+			return nil, nil
+		}
+
+	default:
+		return nil, nil
+	}
+
+	if d.err != nil || !fx.imports(d.imports) {
+		return nil, nil
+	}
+	return fx.newFix(e, start, end, repl)
+}
+
+func (fx *fixer) newFix(e *MismatchError, start, end token.Pos, repl string) (*Fix, error) {
+	pos, endPos := fx.fset.Position(start), fx.fset.Position(end)
+	src, ok := fx.sources[pos.Filename]
+	if !ok {
+		var err error
+		src, err = os.ReadFile(pos.Filename)
+		if err != nil {
+			return nil, err
+		}
+		fx.sources[pos.Filename] = src
+	}
+	if endPos.Offset > len(src) {
+		return nil, fmt.Errorf("%s: source changed since it was loaded", pos.Filename)
+	}
+	return &Fix{
+		Err: e,
+		Pos: pos,
+		End: endPos,
+		Old: string(src[pos.Offset:endPos.Offset]),
+		New: repl,
+	}, nil
+}
+
+// valueSpec returns the spec that declares obj
+// and the index of its name.
+func (fx *fixer) valueSpec(obj types.Object) (*ast.ValueSpec, int) {
+	for _, decl := range fx.file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			spec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range spec.Names {
+				if name.Pos() == obj.Pos() {
+					return spec, i
+				}
+			}
+		}
+	}
+	return nil, 0
+}
+
+// typeSpec returns the spec that declares obj.
+func (fx *fixer) typeSpec(obj types.Object) *ast.TypeSpec {
+	for _, decl := range fx.file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			if spec, ok := spec.(*ast.TypeSpec); ok && spec.Name.Pos() == obj.Pos() {
+				return spec
+			}
+		}
+	}
+	return nil
+}
+
+// structField returns the local field of a struct type spec
+// that differs, if the difference is in a field of the type
+// itself, like Stat.Ino. It only returns fields that are
+// declared by a single name, which may differ from the
+// authoritative one if fields are matched by position.
+func structField(spec *ast.TypeSpec, e *MismatchError) *ast.Field {
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok || strings.Count(e.Path, ".") != 1 || e.ToField == "" || e.ToField == "_" {
+		return nil
+	}
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 1 && field.Names[0].Name == e.ToField {
+			return field
+		}
+	}
+	return nil
+}
+
+// imports reports whether the file being fixed imports the
+// packages without renaming them, the declarer qualifies
+// them by their package name.
+func (fx *fixer) imports(paths stringSet) bool {
+	for _, path := range paths.entries() {
+		imported := false
+		for _, spec := range fx.file.Imports {
+			p, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || p != path {
+				continue
+			}
+			if spec.Name == nil {
+				imported = true
+			}
+		}
+		if !imported {
+			return false
+		}
+	}
+	return true
+}
+
+func tagLiteral(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// ApplyFixes rewrites the files of the fixes. A file that
+// is formatted is formatted after it's fixed. It fails if
+// a file changed since the fixes were made.
+func ApplyFixes(fixes []*Fix) error {
+	files, err := fixFiles(fixes)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		fi, err := os.Stat(f.name)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.name, f.fixed, fi.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteDiff writes the changes ApplyFixes makes as a
+// unified diff, without changing the files.
+func WriteDiff(w io.Writer, fixes []*Fix) error {
+	files, err := fixFiles(fixes)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	for _, f := range files {
+		writeUnifiedDiff(&b, f.name, string(f.src), string(f.fixed))
+	}
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+type fixedFile struct {
+	name       string
+	src, fixed []byte
+}
+
+// fixFiles applies the fixes to the source of their files.
+func fixFiles(fixes []*Fix) ([]fixedFile, error) {
+	byFile := make(map[string][]*Fix)
+	var names []string
+	for _, fix := range fixes {
+		name := fix.Pos.Filename
+		if _, ok := byFile[name]; !ok {
+			names = append(names, name)
+		}
+		byFile[name] = append(byFile[name], fix)
+	}
+	sort.Strings(names)
+
+	files := make([]fixedFile, 0, len(names))
+	for _, name := range names {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		fixed, err := applyFixes(src, byFile[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		files = append(files, fixedFile{name, src, fixed})
+	}
+	return files, nil
+}
+
+func applyFixes(src []byte, fixes []*Fix) ([]byte, error) {
+	fixes = append([]*Fix(nil), fixes...)
+	sort.Slice(fixes, func(i, j int) bool {
+		return fixes[i].Pos.Offset > fixes[j].Pos.Offset
+	})
+	fixed := append([]byte(nil), src...)
+	prev := len(src)
+	for _, fix := range fixes {
+		start, end := fix.Pos.Offset, fix.End.Offset
+		if end > prev {
+			return nil, fmt.Errorf("overlapping fixes at %v", fix.Pos)
+		}
+		if end > len(src) || string(src[start:end]) != fix.Old {
+			return nil, fmt.Errorf("source changed since it was loaded")
+		}
+		fixed = append(fixed[:start:start], append([]byte(fix.New), fixed[end:]...)...)
+		prev = start
+	}
+
+	if formatted, err := format.Source(src); err == nil && bytes.Equal(formatted, src) {
+		return format.Source(fixed)
+	}
+	return fixed, nil
+}
@@ -0,0 +1,152 @@
+package symbolassert
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReport_Fixes(t *testing.T) {
+	from := &PackageProvider{Package: remotepkgLocalImport}
+	if err := from.Load(remotepkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+	to := &PackageProvider{
+		Package:    localpkgLocalImport,
+		BuildTags:  []string{"mismatch"},
+		KeepSyntax: true,
+	}
+	if err := to.Load(localpkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+
+	m := Mapping{
+		{From: "ConstUntypedRune", To: "MismatchUntypedRune"},
+		{From: "ConstUntypedInt", To: "MismatchUntypedInt"},
+		{From: "ConstInt", To: "MismatchInt"},
+		{From: "ConstUntypedFloat", To: "MismatchUntypedFloat"},
+		{From: "ConstUntypedInt", To: "MismatchUint", Duplicate: true},
+		{From: "VarInt", To: "MismatchVarInt"},
+		{From: "Pointer", To: "MismatchPointer"},
+		{From: "Composite", To: "MismatchComposite"},
+		{From: "Sum", To: "MismatchSum"},
+	}
+	r := m.Check(from, to, nil)
+	fixes, err := r.Fixes(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, fix := range fixes {
+		got = append(got, strings.TrimSpace(fix.Old)+" -> "+strings.TrimSpace(fix.New))
+	}
+	want := []string{
+		"'b' -> 'a'",
+		"-1 -> 1",
+		"-1 -> 1",
+		"uint = 2 -> = 1",
+		"2. -> 1.0",
+		"uint -> int",
+		"*uint -> *int",
+		"[15]int8 -> [16]int8",
+	}
+	if !equalStrings(got, want) {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for _, fix := range fixes {
+		if base := filepath.Base(fix.Pos.Filename); base != "mismatch.go" {
+			t.Errorf("%v: fixes %s", fix, base)
+		}
+	}
+
+	t.Run("Diff", func(t *testing.T) {
+		var b bytes.Buffer
+		if err := WriteDiff(&b, fixes[:2]); err != nil {
+			t.Fatal(err)
+		}
+		name := fixes[0].Pos.Filename
+		want := `--- ` + name + `
++++ ` + name + `
+@@ -8,12 +8,12 @@
+ )
+ 
+ const (
+-	MismatchUntypedRune      = 'b'
++	MismatchUntypedRune      = 'a'
+ 	MismatchRune        rune = 'b'
+ )
+ 
+ const (
+-	MismatchUntypedInt      = -1
++	MismatchUntypedInt      = 1
+ 	MismatchInt        int  = -1
+ 	MismatchUint       uint = 2
+ )
+`
+		if got := b.String(); got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		src, err := os.ReadFile(fixes[0].Pos.Filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		copied := filepath.Join(t.TempDir(), "mismatch.go")
+		if err := os.WriteFile(copied, src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for _, fix := range fixes {
+			fix.Pos.Filename = copied
+			fix.End.Filename = copied
+		}
+		if err := ApplyFixes(fixes); err != nil {
+			t.Fatal(err)
+		}
+		fixed, err := os.ReadFile(copied)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range []string{
+			"\tMismatchUntypedInt     = 1\n",
+			"\tMismatchUint           = 1\n",
+			"\tMismatchVarInt    int = 1\n",
+			"\tName     [16]int8\n",
+			"//go:build mismatch\n",
+		} {
+			if !bytes.Contains(fixed, []byte(line)) {
+				t.Errorf("fixed source doesn't contain %q:\n%s", line, fixed)
+			}
+		}
+
+		// applied fixes don't apply again
+		if err := ApplyFixes(fixes); err == nil {
+			t.Error("fixes applied twice")
+		}
+	})
+
+	t.Run("Strictness", func(t *testing.T) {
+		// fields are matched by position, A is fixed as B
+		m := Mapping{{From: "Fields", To: "MismatchFields"}}
+		r := m.Check(from, to, &Config{Strictness: StructurallyEqual})
+		fixes, err := r.Fixes(to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fixes) != 1 {
+			t.Fatalf("got %d fixes, want 1: %v", len(fixes), fixes)
+		}
+		src, err := os.ReadFile(fixes[0].Pos.Filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		line := strings.Split(string(src), "\n")[fixes[0].Pos.Line-1]
+		if want := "\tA int32"; line != want || fixes[0].New != "int64" {
+			t.Errorf("got fix %v of line %q, want fix to int64 of line %q", fixes[0], line, want)
+		}
+	})
+}
@@ -0,0 +1,504 @@
+package symbolassert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/constant"
+	"go/format"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A Generator generates the source of a local package that
+// mirrors symbols of the authoritative package on a list of
+// targets. Constants, types and variables are declared with
+// the same type and value as on the target. Functions aren't
+// declared, they are implemented by hand and the generated
+// source asserts their signature, like:
+//
+//	var _ func(fd int, st *stat) error = fstat
+//
+// Generic functions and methods aren't supported.
+type Generator struct {
+	// Targets are the platforms to generate for. If empty,
+	// the first-class ports are used.
+	Targets []Target
+
+	// From is the import path of the authoritative package,
+	// unqualified symbols are resolved in it.
+	From string
+
+	// Package is the name of the generated package.
+	Package string
+
+	// Symbols maps the authoritative symbols to the local
+	// names they are declared as, it may have patterns.
+	// Local names may be qualified, the qualifier is dropped.
+	// A type that refers to a type of the authoritative
+	// package must be generated with it, under the same name
+	// if the type is embedded.
+	Symbols SymbolMap
+
+	// Transform rewrites the local names of the symbols that
+	// patterns expand to, see ResolveOptions.
+	Transform NameTransform
+
+	// Base is the name of the generated files without the
+	// suffix, see PlanBuild. If empty, "mirror" is used.
+	Base string
+
+	// Parallel limits the number of targets loaded at the
+	// same time, see Matrix.
+	Parallel int
+}
+
+// A GeneratedFile is a file generated by a Generator.
+type GeneratedFile struct {
+	Name   string
+	Source []byte
+}
+
+// Generate generates the files that declare the symbols
+// on every target, grouped by PlanBuild. The files are
+// sorted by name and their source is formatted. The same
+// symbols and targets always generate the same files.
+func (g *Generator) Generate() ([]GeneratedFile, error) {
+	mx := &Matrix{Targets: g.Targets, From: g.From, Parallel: g.Parallel}
+	targets := mx.targets()
+	index := make(map[Target]int, len(targets))
+	for i, t := range targets {
+		index[t] = i
+	}
+
+	var mu sync.Mutex
+	errs := make([]error, len(targets))
+	values := make(map[string][]ValueCell) // local name to cells
+	imports := make(map[string]stringSet)  // declaration to imports
+	missing := make(map[string]int)        // symbol or pattern to targets
+	mx.forEach(func(t Target) {
+		decls, symbols, err := g.declare(t, imports, &mu)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[index[t]] = fmt.Errorf("%v: %w", t, err)
+			return
+		}
+		for _, sym := range symbols {
+			missing[sym]++
+		}
+		for name, decl := range decls {
+			row, ok := values[name]
+			if !ok {
+				row = make([]ValueCell, len(targets))
+				values[name] = row
+			}
+			row[index[t]] = ValueCell{Value: decl, Defined: true}
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	var never []string
+	for sym, n := range missing {
+		if n == len(targets) {
+			never = append(never, sym)
+		}
+	}
+	sort.Strings(never)
+	var errb errorsBuilder
+	for _, sym := range never {
+		if isPattern(sym) {
+			errb = append(errb, &PatternError{Pattern: sym, Err: ErrNoMatch})
+		} else {
+			errb = append(errb, &UnresolvedError{Symbol: sym, Package: g.From})
+		}
+	}
+	if err := errb.Build(); err != nil {
+		return nil, err
+	}
+
+	vt := &ValueTable{Targets: targets}
+	for name, cells := range values {
+		vt.Rows = append(vt.Rows, ValueRow{Symbol: name, Cells: cells})
+	}
+	sort.Slice(vt.Rows, func(i, j int) bool {
+		return vt.Rows[i].Symbol < vt.Rows[j].Symbol
+	})
+
+	base := g.Base
+	if base == "" {
+		base = "mirror"
+	}
+	plan := PlanBuild(vt, base)
+	files := make([]GeneratedFile, 0, len(plan.Files))
+	for _, f := range plan.Files {
+		src, err := g.source(f, imports)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		files = append(files, GeneratedFile{Name: f.Name, Source: src})
+	}
+	return files, nil
+}
+
+// generatedHeader starts every generated file, it follows
+// the convention of go generate.
+const generatedHeader = "// Code generated by symbolassert"
+
+// WriteFiles generates the files and writes them to dir.
+// Files in dir that were generated before with the same
+// base name and aren't generated anymore are removed.
+func (g *Generator) WriteFiles(dir string) error {
+	files, err := g.Generate()
+	if err != nil {
+		return err
+	}
+
+	base := g.Base
+	if base == "" {
+		base = "mirror"
+	}
+	stale, err := filepath.Glob(filepath.Join(dir, base+"_*.go"))
+	if err != nil {
+		return err
+	}
+	stale = append(stale, filepath.Join(dir, base+".go"))
+	generated := make(map[string]bool, len(files))
+	for _, f := range files {
+		generated[f.Name] = true
+	}
+	for _, name := range stale {
+		if generated[filepath.Base(name)] {
+			continue
+		}
+		src, err := os.ReadFile(name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if bytes.HasPrefix(src, []byte(generatedHeader)) {
+			if err := os.Remove(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f.Name), f.Source, 0o666); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// declare returns the declarations of the symbols on target
+// t keyed by local name and the symbols that aren't defined
+// and patterns that match nothing.
+// The imports of the declarations are added to imports,
+// which is guarded by mu.
+func (g *Generator) declare(t Target, imports map[string]stringSet, mu *sync.Mutex) (decls map[string]string, missing []string, err error) {
+	p := t.Provider(g.From)
+	if err := p.Load(g.From); err != nil {
+		return nil, nil, err
+	}
+	m, _, errb := g.Symbols.expand(p, g.Transform)
+	var errs errorsBuilder
+	for _, err := range errb {
+		if e, ok := err.(*PatternError); ok && e.Err == ErrNoMatch {
+			// may match on other targets
+			missing = append(missing, e.Pattern)
+			continue
+		}
+		errs = append(errs, err)
+	}
+	if err := errs.Build(); err != nil {
+		return nil, nil, err
+	}
+
+	// local names of the authoritative objects
+	names := make(map[types.Object]string, len(m))
+	locals := make(map[string]string, len(m))
+	remotes := make([]string, 0, len(m))
+	for remote := range m {
+		remotes = append(remotes, remote)
+	}
+	sort.Strings(remotes)
+	for _, remote := range remotes {
+		local := m[remote]
+		r, err := parseSymbol(remote)
+		if err != nil || len(r.names) != 1 {
+			return nil, nil, fmt.Errorf("can't declare %s", remote)
+		}
+		l, err := parseSymbol(local)
+		if err != nil || len(l.names) != 1 {
+			return nil, nil, fmt.Errorf("can't declare %s as %s", remote, local)
+		}
+		if prev, ok := locals[l.names[0]]; ok {
+			return nil, nil, fmt.Errorf("%s and %s are both declared as %s", prev, remote, l.names[0])
+		}
+		locals[l.names[0]] = remote
+	}
+	for _, remote := range remotes {
+		obj := p.Lookup(remote)
+		if obj == nil {
+			missing = append(missing, remote)
+			continue
+		}
+		l, _ := parseSymbol(m[remote])
+		names[obj] = l.names[0]
+	}
+
+	decls = make(map[string]string, len(names))
+	for obj, name := range names {
+		d := &declarer{from: obj.Pkg(), names: names}
+		decl := d.declare(obj, name)
+		if d.err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", objectKey(obj), d.err)
+		}
+		decls[name] = decl
+
+		mu.Lock()
+		if _, ok := imports[decl]; !ok {
+			imports[decl] = d.imports
+		}
+		mu.Unlock()
+	}
+	return decls, missing, nil
+}
+
+// A declarer writes the declaration of an object
+// of the authoritative package with a local name.
+type declarer struct {
+	from    *types.Package
+	names   map[types.Object]string // local names
+	imports stringSet
+	err     error
+}
+
+// localRef marks a reference to an object of the authoritative
+// package in a type string, see declarer.qualifier.
+var localRef = regexp.MustCompile(`\x00\.([\p{L}_][\p{L}\p{N}_]*)`)
+
+func (d *declarer) declare(obj types.Object, name string) string {
+	switch obj := obj.(type) {
+	case *types.Const:
+		if isUntyped(obj.Type()) {
+			return "const " + name + " = " + constLiteral(obj.Val(), obj.Type())
+		}
+		return "const " + name + " " + d.typeString(obj.Type()) + " = " + constLiteral(obj.Val(), obj.Type())
+	case *types.TypeName:
+		if obj.IsAlias() {
+			return "type " + name + " = " + d.typeString(obj.Type())
+		}
+		var tparams string
+		if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+			// written after the qualified name
+			s := types.TypeString(named, d.qualifier)
+			tparams = s[strings.IndexByte(s, '['):]
+			tparams = d.rename(tparams)
+		}
+		return "type " + name + tparams + " " + d.underlying(obj.Type().Underlying())
+	case *types.Var:
+		return "var " + name + " " + d.typeString(obj.Type())
+	case *types.Func:
+		if sig, ok := obj.Type().(*types.Signature); ok && sig.TypeParams().Len() > 0 {
+			d.err = fmt.Errorf("can't assert the signature of generic function %s", obj.Name())
+			return ""
+		}
+		return "var _ " + d.typeString(obj.Type()) + " = " + name
+	}
+	d.err = fmt.Errorf("can't declare %v", obj)
+	return ""
+}
+
+// underlying writes the underlying type of a declared type,
+// it writes struct tags as raw strings. An embedded field is
+// named by its type, so the type must keep its name.
+func (d *declarer) underlying(typ types.Type) string {
+	st, ok := typ.(*types.Struct)
+	if !ok || st.NumFields() == 0 {
+		return d.typeString(typ)
+	}
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		typ := d.typeString(f.Type())
+		if !f.Embedded() {
+			b.WriteString(f.Name())
+			b.WriteString(" ")
+		} else if name := embeddedName(typ); name != f.Name() && d.err == nil {
+			d.err = fmt.Errorf("embeds %s, which is declared as %s", f.Name(), name)
+		}
+		b.WriteString(typ)
+		if tag := st.Tag(i); tag != "" {
+			b.WriteString(" ")
+			if strings.Contains(tag, "`") {
+				b.WriteString(strconv.Quote(tag))
+			} else {
+				b.WriteString("`" + tag + "`")
+			}
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// embeddedName returns the name of an embedded field of
+// type typ, like T for *pkg.T[int].
+func embeddedName(typ string) string {
+	typ = strings.TrimPrefix(typ, "*")
+	if i := strings.IndexByte(typ, '['); i >= 0 {
+		typ = typ[:i]
+	}
+	return typ[strings.LastIndexByte(typ, '.')+1:]
+}
+
+func (d *declarer) typeString(typ types.Type) string {
+	return d.rename(types.TypeString(typ, d.qualifier))
+}
+
+// qualifier marks the references to the authoritative
+// package and records the other packages as imports.
+func (d *declarer) qualifier(pkg *types.Package) string {
+	if pkg == d.from {
+		return "\x00"
+	}
+	d.imports.Add(pkg.Path())
+	return pkg.Name()
+}
+
+// rename replaces the marked references by local names.
+func (d *declarer) rename(s string) string {
+	return localRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := ref[2:]
+		if local, ok := d.names[d.from.Scope().Lookup(name)]; ok {
+			return local
+		}
+		if d.err == nil {
+			d.err = fmt.Errorf("refers to %s, which isn't declared", name)
+		}
+		return name
+	})
+}
+
+func isUntyped(typ types.Type) bool {
+	basic, ok := typ.(*types.Basic)
+	return ok && basic.Info()&types.IsUntyped != 0
+}
+
+// constLiteral returns an expression of the exact value of
+// a constant that has the same kind if it is untyped.
+func constLiteral(val constant.Value, typ types.Type) string {
+	if basic, ok := typ.(*types.Basic); ok && basic.Kind() == types.UntypedRune {
+		if r, ok := constant.Int64Val(val); ok {
+			return strconv.QuoteRune(rune(r))
+		}
+	}
+	switch val.Kind() {
+	case constant.Float:
+		return floatLiteral(val)
+	case constant.Complex:
+		re, im := numberLiteral(constant.Real(val)), numberLiteral(constant.Imag(val))
+		if strings.Contains(im, " ") {
+			return "complex(" + re + ", " + im + ")"
+		}
+		return "(" + re + " + " + im + "i)"
+	}
+	return val.ExactString()
+}
+
+func numberLiteral(val constant.Value) string {
+	if i := constant.ToInt(val); i.Kind() == constant.Int {
+		return i.ExactString()
+	}
+	return floatLiteral(val)
+}
+
+// floatLiteral returns the shortest literal of a float value
+// that is exact, or a quotient of two literals.
+func floatLiteral(val constant.Value) string {
+	s := val.String()
+	if lit := constant.MakeFromLiteral(s, token.FLOAT, 0); lit.Kind() == constant.Unknown ||
+		!constant.Compare(lit, token.EQL, val) {
+		s = val.ExactString()
+	}
+	if i := strings.IndexByte(s, '/'); i >= 0 {
+		return s[:i] + ".0 / " + s[i+1:]
+	}
+	if !strings.ContainsAny(s, ".eEpP") {
+		s += ".0"
+	}
+	return s
+}
+
+// source returns the formatted source of a planned file.
+// Constants and variables are declared in blocks, before
+// types, signatures are asserted last.
+func (g *Generator) source(f *PlanFile, imports map[string]stringSet) ([]byte, error) {
+	var paths stringSet
+	var consts, vars, others, asserts []string
+	for _, sym := range f.Symbols {
+		for _, path := range imports[sym.Value].entries() {
+			paths.Add(path)
+		}
+		switch {
+		case strings.HasPrefix(sym.Value, "const "):
+			consts = append(consts, strings.TrimPrefix(sym.Value, "const "))
+		case strings.HasPrefix(sym.Value, "var _ "):
+			asserts = append(asserts, sym.Value)
+		case strings.HasPrefix(sym.Value, "var "):
+			vars = append(vars, strings.TrimPrefix(sym.Value, "var "))
+		default:
+			others = append(others, sym.Value)
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s from %s; DO NOT EDIT.\n\n", generatedHeader, g.From)
+	if f.Constraint != "" {
+		fmt.Fprintf(&b, "//go:build %s\n\n", f.Constraint)
+	}
+	fmt.Fprintf(&b, "package %s\n", g.Package)
+	if len(paths) > 0 {
+		sorted := paths.entries()
+		sort.Strings(sorted)
+		b.WriteString("\nimport (\n")
+		for _, path := range sorted {
+			fmt.Fprintf(&b, "\t%q\n", path)
+		}
+		b.WriteString(")\n")
+	}
+	for _, block := range []struct {
+		keyword string
+		decls   []string
+	}{{"const", consts}, {"var", vars}} {
+		switch len(block.decls) {
+		case 0:
+		case 1:
+			fmt.Fprintf(&b, "\n%s %s\n", block.keyword, block.decls[0])
+		default:
+			fmt.Fprintf(&b, "\n%s (\n", block.keyword)
+			for _, decl := range block.decls {
+				fmt.Fprintf(&b, "\t%s\n", decl)
+			}
+			b.WriteString(")\n")
+		}
+	}
+	for _, decl := range append(others, asserts...) {
+		fmt.Fprintf(&b, "\n%s\n", decl)
+	}
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,213 @@
+package symbolassert
+
+import (
+	"errors"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+func TestGenerator(t *testing.T) {
+	g := &Generator{
+		Targets: []Target{
+			{GOOS: "darwin", GOARCH: "arm64"},
+			{GOOS: "linux", GOARCH: "386"},
+			{GOOS: "linux", GOARCH: "amd64"},
+		},
+		From:    remotepkgLocalImport,
+		Package: "mirror",
+		Symbols: SymbolMap{
+			"AF_*":                     "af*",
+			"ConstUntypedPreciseFloat": "precise",
+			"ConstUntypedRune":         "letter",
+			"ConstUntypedComplex":      "complexValue",
+			"Stat":                     "stat",
+			"Timespec":                 "Timespec",
+			"Fstat":                    "fstat",
+			"Func":                     "Func",
+			"Bool":                     "Bool",
+			"Rune":                     "Rune",
+			"Int":                      "Int",
+		},
+		Transform: Lower,
+	}
+	files, err := g.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"mirror.go": `// Code generated by symbolassert from ` + remotepkgLocalImport + `; DO NOT EDIT.
+
+package mirror
+
+const (
+	afinet       = 2
+	afinet6      = 10
+	afunix       = 1
+	complexValue = (1 + 1i)
+	letter       = 'a'
+	precise      = 0.1
+)
+
+type Timespec struct {
+	Sec  int64
+	Nsec int64
+}
+
+type stat struct {
+	Dev  uint64
+	Ino  uint64
+	Mode uint32 ` + "`json:\"mode\"`" + `
+	Timespec
+}
+
+var _ func(fd int, st *stat) error = fstat
+`,
+		"mirror_linux.go": `// Code generated by symbolassert from ` + remotepkgLocalImport + `; DO NOT EDIT.
+
+//go:build linux
+
+package mirror
+
+type Bool bool
+
+type Int int
+
+type Rune rune
+`,
+		"mirror_linux_amd64.go": `// Code generated by symbolassert from ` + remotepkgLocalImport + `; DO NOT EDIT.
+
+//go:build linux && amd64
+
+package mirror
+
+var _ func(b Bool, r Rune, i Int) error = Func
+`,
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+		if got := string(f.Source); got != want[f.Name] {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", f.Name, got, want[f.Name])
+		}
+	}
+	if got, want := strings.Join(names, " "), "mirror.go mirror_linux.go mirror_linux_amd64.go"; got != want {
+		t.Errorf("got files %s, want %s", got, want)
+	}
+
+	t.Run("Deterministic", func(t *testing.T) {
+		again, err := g.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, f := range again {
+			if string(f.Source) != string(files[i].Source) {
+				t.Errorf("%s differs", f.Name)
+			}
+		}
+	})
+
+	t.Run("Compiles", func(t *testing.T) {
+		// all files are built on linux/amd64
+		check := func(impl string) error {
+			fset := token.NewFileSet()
+			var parsed []*ast.File
+			for _, f := range append(files, GeneratedFile{Name: "impl.go", Source: []byte(impl)}) {
+				file, err := parser.ParseFile(fset, f.Name, f.Source, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				parsed = append(parsed, file)
+			}
+			_, err := new(types.Config).Check("mirror", fset, parsed, nil)
+			return err
+		}
+		impl := `package mirror
+
+func fstat(fd int, st *stat) error { return nil }
+
+func Func(b Bool, r Rune, i Int) error { return nil }
+`
+		if err := check(impl); err != nil {
+			t.Error(err)
+		}
+		if err := check(strings.Replace(impl, "st *stat", "st stat", 1)); err == nil {
+			t.Error("signature of fstat isn't asserted")
+		}
+	})
+
+	t.Run("Undeclared", func(t *testing.T) {
+		g := &Generator{
+			Targets: g.Targets[:1],
+			From:    remotepkgLocalImport,
+			Package: "mirror",
+			Symbols: SymbolMap{"Stat": "Stat"},
+		}
+		_, err := g.Generate()
+		if err == nil || !strings.Contains(err.Error(), "refers to Timespec, which isn't declared") {
+			t.Errorf("got error %v", err)
+		}
+	})
+
+	t.Run("Embedded", func(t *testing.T) {
+		g := &Generator{
+			Targets: g.Targets[:1],
+			From:    remotepkgLocalImport,
+			Package: "mirror",
+			Symbols: SymbolMap{"Stat": "stat", "Timespec": "timespec"},
+		}
+		_, err := g.Generate()
+		if err == nil || !strings.Contains(err.Error(), "embeds Timespec, which is declared as timespec") {
+			t.Errorf("got error %v", err)
+		}
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		g := &Generator{
+			Targets: g.Targets[:1],
+			From:    remotepkgLocalImport,
+			Package: "mirror",
+			Symbols: SymbolMap{"SYS_*": "SYS_*", "Uintptr": "Uintptr"},
+		}
+		_, err := g.Generate()
+		var unresolved *UnresolvedError
+		if !errors.Is(err, ErrNoMatch) || !errors.As(err, &unresolved) || unresolved.Symbol != "Uintptr" {
+			t.Errorf("got error %v", err)
+		}
+	})
+}
+
+func Test_constLiteral(t *testing.T) {
+	tests := []struct {
+		lit  string
+		kind token.Token
+		typ  types.BasicKind
+		want string
+	}{
+		{"1.", token.FLOAT, types.UntypedFloat, "1.0"},
+		{"1.5", token.FLOAT, types.UntypedFloat, "1.5"},
+		{"1e400", token.FLOAT, types.UntypedFloat, "1e+400"},
+		{"0x1p-2", token.FLOAT, types.UntypedFloat, "0.25"},
+		{"97", token.INT, types.UntypedRune, "'a'"},
+		{"-1", token.INT, types.UntypedInt, "-1"},
+		{`"a\tb"`, token.STRING, types.UntypedString, `"a\tb"`},
+		{"2i", token.IMAG, types.UntypedComplex, "(0 + 2i)"},
+		{"0.5i", token.IMAG, types.UntypedComplex, "(0 + 0.5i)"},
+	}
+	for _, tt := range tests {
+		val := constant.MakeFromLiteral(tt.lit, tt.kind, 0)
+		if got := constLiteral(val, types.Typ[tt.typ]); got != tt.want {
+			t.Errorf("constLiteral(%s) = %s, want %s", tt.lit, got, tt.want)
+		}
+	}
+
+	third := constant.BinaryOp(constant.MakeInt64(1), token.QUO, constant.MakeFloat64(3))
+	if got, want := constLiteral(third, types.Typ[types.UntypedFloat]), "1.0 / 3"; got != want {
+		t.Errorf("constLiteral(1/3) = %s, want %s", got, want)
+	}
+}
@@ -1,5 +1,10 @@
 module github.com/dwlnetnl/symbolassert
 
-go 1.16
+go 1.20
 
 require golang.org/x/tools v0.11.0
+
+require (
+	golang.org/x/mod v0.12.0 // indirect
+	golang.org/x/sys v0.10.0 // indirect
+)
@@ -0,0 +1,168 @@
+package symbolassert
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// A Failure is an error reported on a set of targets.
+type Failure struct {
+	Err     error    // error as reported on the first target
+	Targets []Target // targets that report the error, sorted
+}
+
+// GroupFailures folds the errors of the reports that are
+// the same on different targets, like a mismatch between
+// the same symbols with the same values. The failures are
+// ordered by the first target that reports them and the
+// order of its errors.
+func GroupFailures(reports map[Target]*Report) []*Failure {
+	targets := make([]Target, 0, len(reports))
+	for t := range reports {
+		targets = append(targets, t)
+	}
+	sortTargets(targets)
+
+	var failures []*Failure
+	byKey := make(map[string]*Failure)
+	for _, t := range targets {
+		for _, err := range reportErrors(reports[t]) {
+			key := failureKey(err)
+			f, ok := byKey[key]
+			if !ok {
+				f = &Failure{Err: err}
+				byKey[key] = f
+				failures = append(failures, f)
+			}
+			if n := len(f.Targets); n == 0 || f.Targets[n-1] != t {
+				f.Targets = append(f.Targets, t)
+			}
+		}
+	}
+	return failures
+}
+
+// Summary formats the failure with its targets compactly,
+// see FormatTargets.
+func (f *Failure) Summary(all []Target) string {
+	err := f.Err
+	if e, ok := err.(*UnresolvedError); ok {
+		// the targets replace the platform
+		u := *e
+		u.Platform = ""
+		err = &u
+	}
+	return err.Error() + " [" + FormatTargets(f.Targets, all) + "]"
+}
+
+func reportErrors(r *Report) []error {
+	if r == nil || r.Err == nil {
+		return nil
+	}
+	var errs *Errors
+	if errors.As(r.Err, &errs) {
+		return errs.Errs
+	}
+	return []error{r.Err}
+}
+
+// failureKey identifies an error independent of the
+// target it's reported on.
+func failureKey(err error) string {
+	switch e := err.(type) {
+	case *MismatchError:
+		var from, to string
+		if e.From != nil {
+			from = objectKey(e.From)
+		}
+		if e.To != nil {
+			to = objectKey(e.To)
+		}
+		return "mismatch\x00" + from + "\x00" + to + "\x00" + e.Error()
+	case *UnresolvedError:
+		return "unresolved\x00" + e.Symbol + "\x00" + e.Origin + "\x00" + e.Package
+	}
+	return "error\x00" + err.Error()
+}
+
+func sortTargets(targets []Target) {
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].String() < targets[j].String()
+	})
+}
+
+// FormatTargets formats a set of targets compactly,
+// relative to all targets that are checked, like
+// "linux/{amd64,arm64}", "darwin/*" if it holds all
+// targets of darwin, or "all except windows/*" if it
+// holds most targets.
+func FormatTargets(targets, all []Target) string {
+	in := make(map[Target]bool, len(targets))
+	for _, t := range targets {
+		in[t] = true
+	}
+	var complement []Target
+	for _, t := range all {
+		if !in[t] {
+			complement = append(complement, t)
+		}
+	}
+	if len(complement) == 0 && len(targets) > 0 {
+		return "all"
+	}
+
+	if len(complement) < len(targets) {
+		return "all except " + formatTargets(complement, all)
+	}
+	return formatTargets(targets, all)
+}
+
+// targetGroup groups targets that only differ in GOARCH.
+type targetGroup struct {
+	goos  string
+	extra string // build tags and cgo
+}
+
+func groupOf(t Target) targetGroup {
+	return targetGroup{t.GOOS, strings.TrimPrefix(t.String(), t.GOOS+"/"+t.GOARCH)}
+}
+
+func formatTargets(targets, all []Target) string {
+	total := make(map[targetGroup]int)
+	for _, t := range all {
+		total[groupOf(t)]++
+	}
+	archs := make(map[targetGroup][]string)
+	var groups []targetGroup
+	for _, t := range targets {
+		g := groupOf(t)
+		if _, ok := archs[g]; !ok {
+			groups = append(groups, g)
+		}
+		archs[g] = append(archs[g], t.GOARCH)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].goos != groups[j].goos {
+			return groups[i].goos < groups[j].goos
+		}
+		return groups[i].extra < groups[j].extra
+	})
+
+	parts := make([]string, 0, len(groups))
+	for _, g := range groups {
+		a := archs[g]
+		sort.Strings(a)
+		var arch string
+		switch {
+		case len(a) == total[g] && len(a) > 1:
+			arch = "*"
+		case len(a) == 1:
+			arch = a[0]
+		default:
+			arch = "{" + strings.Join(a, ",") + "}"
+		}
+		parts = append(parts, g.goos+"/"+arch+g.extra)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,19 @@
+package localpkg
+
+import "encoding/binary"
+
+type AliasType = binary.ByteOrder
+
+type AliasChain = AliasType
+
+type ByteOrder interface {
+	Uint16([]byte) uint16
+	Uint32([]byte) uint32
+	Uint64([]byte) uint64
+	PutUint16([]byte, uint16)
+	PutUint32([]byte, uint32)
+	PutUint64([]byte, uint64)
+	String() string
+}
+
+type AliasByteOrder = ByteOrder
@@ -1,5 +1,7 @@
 package localpkg
 
+import "errors"
+
 const (
 	ConstUntypedBool      = true
 	ConstBool        Bool = true
@@ -76,3 +78,26 @@ type Method struct{}
 func (Method) Method(b Bool, r Rune, i Int) error {
 	return nil
 }
+
+var (
+	VarInt    int = 1
+	VarString     = "string"
+	VarError      = errors.New("error")
+)
+
+type (
+	Pointer   *int
+	Slice     []byte
+	Array     [4]int8
+	Map       map[string]int
+	Chan      chan int
+	RecvChan  <-chan int
+	Signature func(int, ...string) (bool, error)
+)
+
+type Composite struct {
+	Name     [16]int8
+	Next     *int64
+	Data     []uint32
+	Callback func(uintptr) error
+}
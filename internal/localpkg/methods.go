@@ -0,0 +1,45 @@
+package localpkg
+
+type Embedded struct{}
+
+func (Embedded) Promoted() int { return 0 }
+
+type Receiver struct {
+	Embedded
+}
+
+func (Receiver) Value() {}
+
+func (*Receiver) Pointer() {}
+
+type ReceiverKind struct {
+	Embedded
+}
+
+func (*ReceiverKind) Value() {}
+
+func (ReceiverKind) Pointer() {}
+
+type ReceiverPromoted struct{}
+
+func (ReceiverPromoted) Value() {}
+
+func (*ReceiverPromoted) Pointer() {}
+
+type ReceiverExtra struct {
+	Embedded
+}
+
+func (ReceiverExtra) Value() {}
+
+func (*ReceiverExtra) Pointer() {}
+
+func (*ReceiverExtra) Extra() {}
+
+type ReceiverSignature struct {
+	Embedded
+}
+
+func (ReceiverSignature) Value() int { return 0 }
+
+func (*ReceiverSignature) Pointer() {}
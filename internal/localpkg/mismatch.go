@@ -29,3 +29,55 @@ const (
 )
 
 func (Method) MismatchMethod() {}
+
+var (
+	MismatchVarInt    uint = 1
+	MismatchVarString int  = 1
+)
+
+type (
+	MismatchPointer   *uint
+	MismatchSlice     []rune
+	MismatchArray     [8]int8
+	MismatchMap       map[int]int
+	MismatchRecvChan  chan<- int
+	MismatchSignature func(int, []string) (bool, error)
+)
+
+type MismatchFields struct {
+	B int32
+	A int32
+}
+
+type MismatchComposite struct {
+	Name     [15]int8
+	Next     *int64
+	Data     []uint32
+	Callback func(uintptr) error
+}
+
+type MismatchNumber interface {
+	int | ~int64 | ~float64
+}
+
+type MismatchPair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+type MismatchSet[T comparable] map[T]bool
+
+type MismatchEntry struct {
+	Pair Pair[string, uint]
+}
+
+func MismatchSum[T Number](s []T) (sum T) {
+	for _, v := range s {
+		sum += v
+	}
+	return sum
+}
+
+func MismatchApply[T, U any](s []U, f func(T) U) []U {
+	return s
+}
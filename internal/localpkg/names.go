@@ -0,0 +1,7 @@
+package localpkg
+
+const (
+	AfUnix  = 0x1
+	AfInet  = 0x2
+	AfInet6 = 0xa
+)
@@ -0,0 +1,83 @@
+package localpkg
+
+type Timespec struct {
+	Sec  int64
+	Nsec int64
+}
+
+type Stat struct {
+	Dev  uint64
+	Ino  uint64
+	Mode uint32 `json:"mode"`
+	Timespec
+}
+
+type StatName struct {
+	Dev   uint64
+	Inode uint64
+	Mode  uint32 `json:"mode"`
+	Timespec
+}
+
+type StatOrder struct {
+	Ino  uint64
+	Dev  uint64
+	Mode uint32 `json:"mode"`
+	Timespec
+}
+
+type StatTag struct {
+	Dev  uint64
+	Ino  uint64
+	Mode uint32
+	Timespec
+}
+
+type StatEmbedding struct {
+	Dev      uint64
+	Ino      uint64
+	Mode     uint32 `json:"mode"`
+	Timespec Timespec
+}
+
+type StatLayout struct {
+	Dev  uint64
+	Ino  uint32
+	Mode uint32
+	Timespec
+}
+
+type Stamp struct {
+	Time Timespec
+}
+
+type StampNsec struct {
+	Time TimespecNsec
+}
+
+type TimespecNsec struct {
+	Sec  int64
+	Nsec uint64
+}
+
+type StatCopy struct {
+	Dev  uint64
+	Ino  uint64
+	Mode uint32 `json:"mode"`
+	Timespec
+}
+
+type Node struct {
+	Next *Node
+	Stat *Stat
+}
+
+func Fstat(fd int, st *Stat) error {
+	return nil
+}
+
+func FstatCopy(fd int, st *StatCopy) error {
+	return nil
+}
+
+var Head *Node
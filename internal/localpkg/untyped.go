@@ -0,0 +1,10 @@
+package localpkg
+
+const (
+	TypedUntypedInt   uint32  = 1
+	TypedUntypedRune  int32   = 'a'
+	TypedUntypedFloat float32 = 1.
+	TypedNegInt       uint32  = 1<<32 - 1
+	TypedPreciseFloat float32 = 0.1
+	TypedBool         bool    = true
+)
@@ -7,3 +7,5 @@ type AliasType = binary.ByteOrder
 const AliasConst = binary.MaxVarintLen64
 
 var AliasVar = binary.LittleEndian
+
+type AliasChain = AliasType
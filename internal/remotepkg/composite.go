@@ -0,0 +1,18 @@
+package remotepkg
+
+type (
+	Pointer   *int
+	Slice     []byte
+	Array     [4]int8
+	Map       map[string]int
+	Chan      chan int
+	RecvChan  <-chan int
+	Signature func(int, ...string) (bool, error)
+)
+
+type Composite struct {
+	Name     [16]int8
+	Next     *int64
+	Data     []uint32
+	Callback func(uintptr) error
+}
@@ -34,3 +34,14 @@ const (
 	ConstUntypedString        = "string"
 	ConstString        string = "string"
 )
+
+const (
+	ConstUntypedNegInt       = -1
+	ConstUntypedPreciseFloat = 0.1
+)
+
+const (
+	AF_UNIX  = 0x1
+	AF_INET  = 0x2
+	AF_INET6 = 0xa
+)
@@ -0,0 +1,42 @@
+package remotepkg
+
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+type Pair[K, V comparable] struct {
+	Key   K
+	Value V
+}
+
+func (p Pair[K, V]) Swap() Pair[V, K] {
+	return Pair[V, K]{p.Value, p.Key}
+}
+
+type Set[T comparable] map[T]struct{}
+
+type Entry struct {
+	Pair Pair[string, int]
+}
+
+func Sum[T Number](s ...T) (sum T) {
+	for _, v := range s {
+		sum += v
+	}
+	return sum
+}
+
+func Apply[T, U any](s []T, f func(T) U) []U {
+	r := make([]U, len(s))
+	for i, v := range s {
+		r[i] = f(v)
+	}
+	return r
+}
+
+type List[T any] struct {
+	Next  *List[T]
+	Value T
+}
+
+var Ints List[int]
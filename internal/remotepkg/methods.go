@@ -0,0 +1,13 @@
+package remotepkg
+
+type Embedded struct{}
+
+func (Embedded) Promoted() int { return 0 }
+
+type Receiver struct {
+	Embedded
+}
+
+func (Receiver) Value() {}
+
+func (*Receiver) Pointer() {}
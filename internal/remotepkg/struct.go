@@ -0,0 +1,33 @@
+package remotepkg
+
+type Timespec struct {
+	Sec  int64
+	Nsec int64
+}
+
+type Stat struct {
+	Dev  uint64
+	Ino  uint64
+	Mode uint32 `json:"mode"`
+	Timespec
+}
+
+type Stamp struct {
+	Time Timespec
+}
+
+type Fields struct {
+	A int32
+	B int64
+}
+
+type Node struct {
+	Next *Node
+	Stat *Stat
+}
+
+func Fstat(fd int, st *Stat) error {
+	return nil
+}
+
+var Head *Node
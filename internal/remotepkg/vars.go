@@ -0,0 +1,9 @@
+package remotepkg
+
+import "errors"
+
+var (
+	VarInt    int = 1
+	VarString     = "string"
+	VarError      = errors.New("error")
+)
@@ -0,0 +1,271 @@
+package symbolassert
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+)
+
+// A Mapping maps authoritative symbols to local symbols,
+// like a SymbolMap, as a list of entries. A symbol may be
+// mapped more than once on either side, so a constant can
+// be mirrored by both a typed and an untyped copy. Errors
+// are reported in the order the entries are declared.
+//
+// Symbols are looked up as written, patterns and name
+// transforms are only supported by SymbolMap.
+type Mapping []Entry
+
+// An Entry maps an authoritative symbol to a local symbol.
+type Entry struct {
+	From string // authoritative symbol
+	To   string // local symbol
+
+	// Duplicate declares that the symbols are intentionally
+	// mapped by an earlier entry as well. Otherwise Resolve
+	// reports a DuplicateError.
+	Duplicate bool
+
+	// Note describes the entry, it isn't interpreted.
+	Note string
+
+	// Constraint is a build constraint expression, like
+	// "linux && !386", that limits the entry to the platforms
+	// of the authoritative provider that satisfy it.
+	Constraint string
+
+	// Diverges is the reason the symbols are expected to
+	// differ. If set, their mismatches aren't errors, but
+	// it's an error if they don't differ.
+	Diverges string
+
+	// Config overrides the configuration used to compare
+	// the symbols of the entry. If its Sizes is nil, the
+	// sizes of the overridden configuration are used.
+	Config *Config
+}
+
+// Resolve resolves the mapping to a list of object pairs
+// in the same order.
+func (m Mapping) Resolve(from, to Provider) (ObjectPairs, error) {
+	return m.ResolveWith(from, to, nil)
+}
+
+// ResolveWith is like Resolve with options, which may be nil.
+// The Transform option is ignored. Entries with a constraint
+// that isn't satisfied are left out.
+func (m Mapping) ResolveWith(from, to Provider, opts *ResolveOptions) (ObjectPairs, error) {
+	pairs, _, errb := m.resolve(from, to, opts)
+	return pairs, errb.Build()
+}
+
+func (m Mapping) resolve(from, to Provider, opts *ResolveOptions) (pairs ObjectPairs, skipped []Skipped, errb errorsBuilder) {
+	if opts == nil {
+		opts = &ResolveOptions{}
+	}
+	suggestions := opts.Suggestions
+	if suggestions == 0 {
+		suggestions = defaultSuggestions
+	}
+
+	declared := make(map[types.Object]int) // entry that maps an object first
+	for i, e := range m {
+		ok, err := matchConstraint(e.Constraint, from)
+		if err != nil {
+			errb = append(errb, fmt.Errorf("entry %d: %w", i, err))
+			continue
+		}
+		if !ok {
+			skipped = append(skipped, Skipped{Entry: e, Reason: "constraint " + e.Constraint})
+			continue
+		}
+
+		objFrom := from.Lookup(e.From)
+		objTo := to.Lookup(e.To)
+		switch {
+		case objFrom == nil:
+			errb = append(errb, unresolved(from, e.From, "", suggestions))
+			continue
+		case objTo == nil:
+			errb = append(errb, unresolved(to, e.To, "", suggestions))
+			continue
+		}
+
+		for _, dup := range []struct {
+			obj    types.Object
+			symbol string
+		}{{objFrom, e.From}, {objTo, e.To}} {
+			if prev, ok := declared[dup.obj]; !ok {
+				declared[dup.obj] = i
+			} else if !e.Duplicate {
+				errb = append(errb, &DuplicateError{Symbol: dup.symbol, Entry: i, Previous: prev})
+			}
+		}
+		pairs = append(pairs, ObjectPair{From: objFrom, To: objTo, Path: selectorPath(e.From, objFrom), Entry: &m[i]})
+	}
+	return pairs, skipped, errb
+}
+
+// DuplicateError is returned from Mapping.Resolve if an
+// entry maps a symbol that an earlier entry maps as well
+// and isn't declared a duplicate.
+type DuplicateError struct {
+	Symbol   string
+	Entry    int // index of the entry
+	Previous int // index of the earlier entry
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("duplicate symbol: %s (entry %d, previously entry %d)",
+		e.Symbol, e.Entry, e.Previous)
+}
+
+// An ObjectPair pairs an authoritative object with the
+// local object that mirrors it.
+type ObjectPair struct {
+	From, To types.Object
+
+	// Path is the selector the authoritative object is looked
+	// up by, like Node.Next. It roots the paths of mismatches
+	// and orders pairs with Config.SortByKey, the name of the
+	// object is used if it's empty.
+	Path string
+
+	// Entry is the entry the pair is resolved from, if any.
+	Entry *Entry
+}
+
+// path returns the root of the paths of mismatches.
+func (p ObjectPair) path() string {
+	if p.Path == "" {
+		return p.From.Name()
+	}
+	return p.Path
+}
+
+// key returns the authoritative symbol of the pair.
+func (p ObjectPair) key() string {
+	if p.Path == "" || p.From.Pkg() == nil {
+		return objectKey(p.From)
+	}
+	return p.From.Pkg().Path() + "." + p.Path
+}
+
+// ObjectPairs is a list of object pairs, like ObjectMap
+// but ordered and an object may occur more than once.
+type ObjectPairs []ObjectPair
+
+// ComparePairs is like Compare, it compares the pairs in
+// order. With Config.SortByKey they are ordered by the
+// authoritative symbol, pairs with the same authoritative
+// symbol keep their order. The options of the entries the
+// pairs are resolved from are honored.
+func ComparePairs(pairs ObjectPairs, cfg *Config) error {
+	errb, _ := comparePairs(pairs, cfg)
+	return errb.Build()
+}
+
+func comparePairs(pairs ObjectPairs, cfg *Config) (errb errorsBuilder, diverged []Skipped) {
+	c := &comparer{cfg: cfg.normalize(), objects: pairs}
+	if c.cfg.SortByKey {
+		pairs = append(ObjectPairs(nil), pairs...)
+		sort.SliceStable(pairs, func(i, j int) bool {
+			return pairs[i].key() < pairs[j].key()
+		})
+	}
+	defaults := c.cfg
+	for _, p := range pairs {
+		if p.Entry == nil {
+			c.compare(p.path(), p.From, p.To)
+			continue
+		}
+
+		if p.Entry.Config != nil {
+			c.cfg = p.Entry.Config.normalize()
+			if c.cfg.Sizes == nil {
+				c.cfg.Sizes = defaults.Sizes
+			}
+		}
+		n := len(c.errb)
+		c.compare(p.path(), p.From, p.To)
+		c.cfg = defaults
+
+		if p.Entry.Diverges != "" {
+			errs := append([]error(nil), c.errb[n:]...)
+			c.errb = c.errb[:n]
+			if len(errs) == 0 {
+				c.errb = append(c.errb, &UnexpectedMatchError{Entry: *p.Entry})
+			} else {
+				diverged = append(diverged, Skipped{Entry: *p.Entry, Reason: p.Entry.Diverges, Errs: errs})
+			}
+		}
+	}
+	return c.errb, diverged
+}
+
+// UnexpectedMatchError is returned from ComparePairs if
+// the symbols of an entry that is expected to diverge match.
+type UnexpectedMatchError struct {
+	Entry Entry
+}
+
+func (e *UnexpectedMatchError) Error() string {
+	return fmt.Sprintf("expected divergence: %s -> %s: %s", e.Entry.From, e.Entry.To, e.Entry.Diverges)
+}
+
+// Check resolves and compares the mapping, see Resolve and
+// ComparePairs. The configuration may be nil. If its Sizes
+// is nil, the sizes of from are used if it has them, like
+// those of a PackageProvider.
+func (m Mapping) Check(from, to Provider, cfg *Config) *Report {
+	pairs, skipped, errb := m.resolve(from, to, nil)
+	cmpErrb, diverged := comparePairs(pairs, withSizes(cfg, from))
+	return &Report{
+		Pairs:    pairs,
+		Skipped:  skipped,
+		Diverged: diverged,
+		Err:      append(errb, cmpErrb...).Build(),
+	}
+}
+
+// Check resolves and compares the symbol map, see Resolve
+// and Compare. The pairs of the report are ordered by the
+// authoritative symbol. The configuration may be nil, its
+// Sizes default to those of from like in Mapping.Check.
+func (m SymbolMap) Check(from, to Provider, cfg *Config) *Report {
+	pairs, errb := m.resolve(from, to, nil, defaultSuggestions)
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].key() < pairs[j].key()
+	})
+	cmpErrb, _ := comparePairs(pairs, withSizes(cfg, from))
+	return &Report{Pairs: pairs, Err: append(errb, cmpErrb...).Build()}
+}
+
+// withSizes returns cfg with the sizes of p if it has none.
+func withSizes(cfg *Config, p Provider) *Config {
+	sp, ok := p.(interface{ Sizes() types.Sizes })
+	if !ok || cfg != nil && cfg.Sizes != nil {
+		return cfg
+	}
+	var c Config
+	if cfg != nil {
+		c = *cfg
+	}
+	c.Sizes = sp.Sizes()
+	return &c
+}
+
+// A Report is the outcome of Mapping.Check or SymbolMap.Check.
+type Report struct {
+	Pairs    ObjectPairs // resolved pairs
+	Skipped  []Skipped   // entries with a constraint that isn't satisfied
+	Diverged []Skipped   // entries that differ as expected
+	Err      error       // resolve and compare errors
+}
+
+// Skipped is an entry that isn't checked.
+type Skipped struct {
+	Entry  Entry
+	Reason string  // constraint or expected divergence
+	Errs   []error // mismatches of a diverging entry
+}
@@ -0,0 +1,187 @@
+package symbolassert
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMapping(t *testing.T) {
+	from := &PackageProvider{Package: remotepkgLocalImport}
+	if err := from.Load(remotepkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+	to := &PackageProvider{Package: localpkgLocalImport}
+	if err := to.Load(localpkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+
+	m := Mapping{
+		{From: "ConstUntypedInt", To: "ConstUntypedInt"},
+		{From: "ConstUntypedInt", To: "TypedUntypedInt", Duplicate: true, Note: "typed copy"},
+		{From: "ConstInt", To: "ConstUntypedInt"},
+		{From: "ConstStrng", To: "ConstString"},
+		{From: "remotepkg.ConstInt", To: "ConstInt"},
+	}
+	pairs, err := m.Resolve(from, to)
+
+	var errs *Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("got %T, want: %T", err, errs)
+	}
+	var got []string
+	for _, e := range errs.Errs {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		"duplicate symbol: ConstUntypedInt (entry 2, previously entry 0)",
+		"unresolved symbol: ConstStrng in " + remotepkgFullPkgPath + " [" + from.platform() + "]; did you mean ConstString?",
+		"duplicate symbol: remotepkg.ConstInt (entry 4, previously entry 2)",
+	}
+	if !equalStrings(got, want) {
+		t.Errorf("got %q, want: %q", got, want)
+	}
+
+	if len(pairs) != 4 {
+		t.Fatalf("got %d pairs, want: 4", len(pairs))
+	}
+	for i, want := range []string{"ConstUntypedInt", "TypedUntypedInt", "ConstUntypedInt", "ConstInt"} {
+		if got := pairs[i].To.Name(); got != want {
+			t.Errorf("pair %d: got %s, want: %s", i, got, want)
+		}
+	}
+
+	err = ComparePairs(pairs, nil)
+	got = nil
+	if errors.As(err, &errs) {
+		for _, e := range errs.Errs {
+			got = append(got, e.Error())
+		}
+	}
+	want = []string{
+		"constant type mismatch: ConstUntypedInt (untyped int != uint32)",
+		"constant type mismatch: ConstInt (int != untyped int)",
+	}
+	if !equalStrings(got, want) {
+		t.Errorf("got %q, want: %q", got, want)
+	}
+}
+
+func TestMapping_Check(t *testing.T) {
+	from := &PackageProvider{GOOS: "linux", GOARCH: "amd64", Package: remotepkgLocalImport}
+	if err := from.Load(remotepkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+	to := &PackageProvider{Package: localpkgLocalImport}
+	if err := to.Load(localpkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+
+	m := Mapping{
+		{From: "ConstInt", To: "ConstInt"},
+		{From: "ConstInt64", To: "ConstInt64", Constraint: "windows"},
+		{From: "ConstUint", To: "ConstUint", Constraint: "unix && amd64"},
+		{From: "ConstUntypedInt", To: "TypedUntypedInt", Diverges: "typed copy"},
+		{From: "ConstUntypedInt", To: "TypedUntypedInt", Duplicate: true, Config: &Config{AllowUntyped: true}},
+		{From: "ConstString", To: "ConstString", Diverges: "fixed upstream"},
+		{From: "ConstBool", To: "ConstBool", Constraint: "linux &&"},
+	}
+	r := m.Check(from, to, nil)
+
+	if len(r.Pairs) != 5 {
+		t.Errorf("got %d pairs, want: 5", len(r.Pairs))
+	}
+	if len(r.Skipped) != 1 || r.Skipped[0].Entry.From != "ConstInt64" || r.Skipped[0].Reason != "constraint windows" {
+		t.Errorf("got skipped: %+v", r.Skipped)
+	}
+	if len(r.Diverged) != 1 || r.Diverged[0].Entry.From != "ConstUntypedInt" || len(r.Diverged[0].Errs) != 1 {
+		t.Fatalf("got diverged: %+v", r.Diverged)
+	}
+	var me *MismatchError
+	if !errors.As(r.Diverged[0].Errs[0], &me) || me.Kind != KindMismatch {
+		t.Errorf("got diverging error %v, want: %v", r.Diverged[0].Errs[0], KindMismatch)
+	}
+
+	var errs *Errors
+	if !errors.As(r.Err, &errs) {
+		t.Fatalf("got %T, want: %T", r.Err, errs)
+	}
+	var got []string
+	for _, e := range errs.Errs {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		`entry 6: invalid constraint "linux &&": unexpected end of expression`,
+		"expected divergence: ConstString -> ConstString: fixed upstream",
+	}
+	if !equalStrings(got, want) {
+		t.Errorf("got %q, want: %q", got, want)
+	}
+}
+
+func TestMapping_CheckSizes(t *testing.T) {
+	from := &PackageProvider{GOOS: "linux", GOARCH: "386", Package: remotepkgLocalImport}
+	if err := from.Load(remotepkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+	to := &PackageProvider{Package: localpkgLocalImport}
+	if err := to.Load(localpkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+
+	// uint is as large as uint64 on the host only
+	m := Mapping{{From: "Uint", To: "Uint64"}}
+	r := m.Check(from, to, &Config{Layout: true})
+	var me *MismatchError
+	if !errors.As(r.Err, &me) || me.Kind != SizeMismatch || me.FromValue != int64(4) {
+		t.Errorf("got %v, want size mismatch of 4 bytes", r.Err)
+	}
+}
+
+func TestMapping_CheckSelectors(t *testing.T) {
+	from := &PackageProvider{GOOS: "linux", GOARCH: "amd64", Package: remotepkgLocalImport}
+	if err := from.Load(remotepkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+	to := &PackageProvider{Package: localpkgLocalImport}
+	if err := to.Load(localpkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+
+	// fields of the same name are told apart by their selector
+	m := Mapping{
+		{From: "Node.Next", To: "Composite.Next"},
+		{From: "Composite.Next", To: "Node.Next"},
+	}
+	r := m.Check(from, to, &Config{SortByKey: true})
+	var errs *Errors
+	if !errors.As(r.Err, &errs) {
+		t.Fatalf("got %T, want: %T", r.Err, errs)
+	}
+	var got []string
+	for _, e := range errs.Errs {
+		got = append(got, e.(*MismatchError).Path)
+	}
+	want := []string{"Composite.Next", "Node.Next"}
+	if !equalStrings(got, want) {
+		t.Errorf("got %q, want: %q", got, want)
+	}
+}
+
+func TestMapping_CheckEntrySizes(t *testing.T) {
+	from := &PackageProvider{GOOS: "linux", GOARCH: "386", Package: remotepkgLocalImport}
+	if err := from.Load(remotepkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+	to := &PackageProvider{Package: localpkgLocalImport}
+	if err := to.Load(localpkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+
+	// the override keeps the sizes of from
+	m := Mapping{{From: "Uint", To: "Uint64", Config: &Config{Strictness: LayoutCompatible}}}
+	r := m.Check(from, to, nil)
+	var me *MismatchError
+	if !errors.As(r.Err, &me) || me.Kind != SizeMismatch || me.FromValue != int64(4) {
+		t.Errorf("got %v, want size mismatch of 4 bytes", r.Err)
+	}
+}
@@ -0,0 +1,158 @@
+package symbolassert
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// A Target is a platform to check symbols on.
+type Target struct {
+	GOOS       string
+	GOARCH     string
+	BuildTags  string // comma-separated, like the -tags flag
+	CgoEnabled bool   // sets CGO_ENABLED
+}
+
+func (t Target) String() string {
+	var b strings.Builder
+	b.WriteString(t.GOOS)
+	b.WriteString("/")
+	b.WriteString(t.GOARCH)
+	if t.BuildTags != "" {
+		b.WriteString(" tags=")
+		b.WriteString(t.BuildTags)
+	}
+	if t.CgoEnabled {
+		b.WriteString(" cgo")
+	}
+	return b.String()
+}
+
+// Provider returns a PackageProvider for the target that
+// resolves unqualified identifiers in pkg.
+func (t Target) Provider(pkg string) *PackageProvider {
+	p := &PackageProvider{
+		GOOS:       t.GOOS,
+		GOARCH:     t.GOARCH,
+		CgoEnabled: "0",
+		Package:    pkg,
+	}
+	if t.BuildTags != "" {
+		p.BuildTags = strings.Split(t.BuildTags, ",")
+	}
+	if t.CgoEnabled {
+		p.CgoEnabled = "1"
+	}
+	return p
+}
+
+// FirstClassTargets returns the first-class ports of Go,
+// the platforms a release is blocked on when broken.
+func FirstClassTargets() []Target {
+	return []Target{
+		{GOOS: "darwin", GOARCH: "amd64"},
+		{GOOS: "darwin", GOARCH: "arm64"},
+		{GOOS: "linux", GOARCH: "386"},
+		{GOOS: "linux", GOARCH: "amd64"},
+		{GOOS: "linux", GOARCH: "arm"},
+		{GOOS: "linux", GOARCH: "arm64"},
+		{GOOS: "windows", GOARCH: "386"},
+		{GOOS: "windows", GOARCH: "amd64"},
+	}
+}
+
+// A Checker resolves and compares symbols, it's
+// implemented by SymbolMap and Mapping.
+type Checker interface {
+	Check(from, to Provider, cfg *Config) *Report
+}
+
+var (
+	_ Checker = SymbolMap(nil)
+	_ Checker = Mapping(nil)
+)
+
+// A Matrix checks symbols on a list of targets.
+type Matrix struct {
+	// Targets are the platforms to check. If empty, the
+	// first-class ports are checked.
+	Targets []Target
+
+	// From and To are the import paths of the authoritative
+	// and the local package, unqualified identifiers are
+	// resolved in them.
+	From, To string
+
+	// Config configures the comparison, see Compare.
+	// If Sizes is nil, the sizes of the target are used.
+	Config *Config
+
+	// Parallel limits the number of targets checked at
+	// the same time. If zero, GOMAXPROCS is used.
+	Parallel int
+}
+
+// Run checks symbols on every target in parallel. The reports
+// are keyed by target, if the packages can't be loaded the
+// report only holds the error.
+func (mx *Matrix) Run(c Checker) map[Target]*Report {
+	var mu sync.Mutex
+	reports := make(map[Target]*Report)
+	mx.forEach(func(t Target) {
+		r := mx.check(t, c)
+		mu.Lock()
+		reports[t] = r
+		mu.Unlock()
+	})
+	return reports
+}
+
+// targets returns the targets to check.
+func (mx *Matrix) targets() []Target {
+	if len(mx.Targets) == 0 {
+		return FirstClassTargets()
+	}
+	return mx.Targets
+}
+
+// forEach calls fn for every target in parallel
+// and waits until all calls return.
+func (mx *Matrix) forEach(fn func(t Target)) {
+	parallel := mx.Parallel
+	if parallel <= 0 {
+		parallel = runtime.GOMAXPROCS(0)
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallel)
+	for _, t := range mx.targets() {
+		wg.Add(1)
+		go func(t Target) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn(t)
+		}(t)
+	}
+	wg.Wait()
+}
+
+func (mx *Matrix) check(t Target, c Checker) *Report {
+	from := t.Provider(mx.From)
+	if err := from.Load(mx.From); err != nil {
+		return &Report{Err: err}
+	}
+	to := t.Provider(mx.To)
+	if err := to.Load(mx.To); err != nil {
+		return &Report{Err: err}
+	}
+
+	var cfg Config
+	if mx.Config != nil {
+		cfg = *mx.Config
+	}
+	if cfg.Sizes == nil {
+		cfg.Sizes = from.Sizes()
+	}
+	return c.Check(from, to, &cfg)
+}
@@ -0,0 +1,139 @@
+package symbolassert
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMatrix(t *testing.T) {
+	linux := Target{GOOS: "linux", GOARCH: "amd64"}
+	linux386 := Target{GOOS: "linux", GOARCH: "386", BuildTags: "netgo"}
+	darwin := Target{GOOS: "darwin", GOARCH: "arm64"}
+	mx := &Matrix{
+		Targets: []Target{linux, linux386, darwin},
+		From:    remotepkgLocalImport,
+		To:      localpkgLocalImport,
+		Config:  &Config{Strictness: LayoutCompatible},
+	}
+
+	t.Run("Mapping", func(t *testing.T) {
+		reports := mx.Run(Mapping{
+			{From: "ConstInt", To: "ConstInt"},
+			{From: "Stat", To: "StatName"},
+			{From: "Uint", To: "Uint", Constraint: "linux"},
+		})
+		if len(reports) != 3 {
+			t.Fatalf("got %d reports, want: 3", len(reports))
+		}
+		for _, target := range []Target{linux, linux386} {
+			r := reports[target]
+			if r.Err != nil {
+				t.Errorf("%v: %v", target, r.Err)
+			}
+			if len(r.Pairs) != 3 || len(r.Skipped) != 0 {
+				t.Errorf("%v: got %d pairs and %d skipped, want: 3 and 0", target, len(r.Pairs), len(r.Skipped))
+			}
+		}
+		if r := reports[darwin]; r.Err != nil || len(r.Skipped) != 1 {
+			t.Errorf("%v: got error %v and %d skipped, want: 1 skipped", darwin, r.Err, len(r.Skipped))
+		}
+	})
+
+	t.Run("SymbolMap", func(t *testing.T) {
+		reports := mx.Run(SymbolMap{"Uint": "Uint", "ConstInt": "ConstInt"})
+		if r := reports[linux]; r.Err != nil || len(r.Pairs) != 2 {
+			t.Errorf("%v: got error %v and %d pairs, want: 2 pairs", linux, r.Err, len(r.Pairs))
+		}
+		var ue *UnresolvedError
+		if r := reports[darwin]; !errors.As(r.Err, &ue) || ue.Platform != "darwin/arm64" {
+			t.Errorf("%v: got %v, want: %T", darwin, r.Err, ue)
+		}
+	})
+
+	t.Run("LoadError", func(t *testing.T) {
+		mx := &Matrix{Targets: []Target{linux}, From: "invalid/package/path", To: localpkgLocalImport}
+		if r := mx.Run(SymbolMap{}); r[linux] == nil || r[linux].Err == nil {
+			t.Error("expect error")
+		}
+	})
+}
+
+func TestTarget(t *testing.T) {
+	target := Target{GOOS: "linux", GOARCH: "arm64", BuildTags: "netgo,osusergo", CgoEnabled: true}
+	if got, want := target.String(), "linux/arm64 tags=netgo,osusergo cgo"; got != want {
+		t.Errorf("got %q, want: %q", got, want)
+	}
+	p := target.Provider("unix")
+	if p.GOOS != "linux" || p.GOARCH != "arm64" || p.CgoEnabled != "1" || p.Package != "unix" ||
+		!equalStrings(p.BuildTags, []string{"netgo", "osusergo"}) {
+		t.Errorf("got provider %+v", p)
+	}
+
+	found := false
+	for _, t := range FirstClassTargets() {
+		found = found || t == Target{GOOS: "linux", GOARCH: "amd64"}
+	}
+	if !found {
+		t.Error("linux/amd64 is not a first-class target")
+	}
+}
+
+func TestGroupFailures(t *testing.T) {
+	all := FirstClassTargets()
+	mismatch := func() error {
+		return &MismatchError{Kind: ValueMismatch, Path: "ConstInt", FromValue: 1, ToValue: 2}
+	}
+	reports := make(map[Target]*Report)
+	for _, target := range all {
+		errb := errorsBuilder{mismatch()}
+		if target.GOOS == "linux" {
+			errb = append(errb, &UnresolvedError{Symbol: "O_LARGEFILE", Platform: target.String()})
+		}
+		if target.GOARCH == "386" {
+			errb = append(errb, &MismatchError{Kind: SizeMismatch, Path: "Stat", FromValue: 96, ToValue: 144})
+		}
+		reports[target] = &Report{Err: errb.Build()}
+	}
+	reports[Target{GOOS: "linux", GOARCH: "amd64"}] = &Report{}
+
+	var got []string
+	for _, f := range GroupFailures(reports) {
+		got = append(got, f.Summary(all))
+	}
+	want := []string{
+		"constant value mismatch: ConstInt (1 != 2) [all except linux/amd64]",
+		"unresolved symbol: O_LARGEFILE [linux/{386,arm,arm64}]",
+		"size mismatch: Stat (96 != 144) [linux/386, windows/386]",
+	}
+	if !equalStrings(got, want) {
+		t.Errorf("got %q, want: %q", got, want)
+	}
+}
+
+func TestFormatTargets(t *testing.T) {
+	all := FirstClassTargets()
+	filter := func(keep func(Target) bool) []Target {
+		var targets []Target
+		for _, t := range all {
+			if keep(t) {
+				targets = append(targets, t)
+			}
+		}
+		return targets
+	}
+	for _, c := range []struct {
+		targets []Target
+		want    string
+	}{
+		{all, "all"},
+		{nil, ""},
+		{filter(func(t Target) bool { return t.GOOS != "windows" }), "all except windows/*"},
+		{filter(func(t Target) bool { return t.GOOS == "darwin" }), "darwin/*"},
+		{filter(func(t Target) bool { return t.GOOS == "linux" && t.GOARCH[0] == 'a' }), "linux/{amd64,arm,arm64}"},
+		{[]Target{{GOOS: "linux", GOARCH: "riscv64", BuildTags: "netgo"}}, "linux/riscv64 tags=netgo"},
+	} {
+		if got := FormatTargets(c.targets, all); got != c.want {
+			t.Errorf("got %q, want: %q", got, c.want)
+		}
+	}
+}
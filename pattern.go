@@ -0,0 +1,145 @@
+package symbolassert
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrInvalidPattern is returned if a pattern hasn't
+	// exactly one wildcard on both sides or if the provider
+	// can't list symbols.
+	ErrInvalidPattern = errors.New("invalid pattern")
+
+	// ErrNoMatch is returned if a pattern matches nothing.
+	ErrNoMatch = errors.New("pattern matches nothing")
+
+	// ErrOverlap is returned if a symbol is matched by
+	// more than one pattern.
+	ErrOverlap = errors.New("symbol matched by multiple patterns")
+)
+
+// PatternError is returned from SymbolMap.Resolve if a
+// pattern can't be expanded.
+type PatternError struct {
+	Pattern string
+	Symbol  string // symbol matched, if any
+	Err     error
+}
+
+func (e *PatternError) Error() string {
+	if e.Symbol != "" {
+		return fmt.Sprintf("pattern %s: %v: %s", e.Pattern, e.Err, e.Symbol)
+	}
+	return fmt.Sprintf("pattern %s: %v", e.Pattern, e.Err)
+}
+
+func (e *PatternError) Unwrap() error {
+	return e.Err
+}
+
+func isPattern(symbol string) bool {
+	return strings.Contains(symbol, "*")
+}
+
+// expand returns m with its patterns replaced by the
+// entries for the symbols they match. The local names
+// of these entries are rewritten by transform, if not nil,
+// and origins maps their authoritative symbols to the
+// local names before the rewrite.
+func (m SymbolMap) expand(from Provider, transform NameTransform) (expanded SymbolMap, origins map[string]string, errb errorsBuilder) {
+	var patterns []string
+	for remote, local := range m {
+		if isPattern(remote) || isPattern(local) {
+			patterns = append(patterns, remote)
+		}
+	}
+	if len(patterns) == 0 {
+		return m, nil, nil
+	}
+	sort.Strings(patterns)
+
+	// objects of the entries for a single symbol, which
+	// may be spelled differently than the symbols matched
+	explicit := make(map[types.Object]bool)
+	expanded = make(SymbolMap, len(m))
+	for remote, local := range m {
+		if !isPattern(remote) && !isPattern(local) {
+			expanded[remote] = local
+			if obj := from.Lookup(remote); obj != nil {
+				explicit[obj] = true
+			}
+		}
+	}
+
+	// pattern that matched a symbol
+	remoteOwners := make(map[string]string)
+	localOwners := make(map[string]string)
+	for _, pattern := range patterns {
+		local := m[pattern]
+		sel, err := parseSymbol(pattern)
+		lister, ok := from.(Lister)
+		if err != nil || !ok || len(sel.names) != 1 ||
+			strings.Count(sel.names[0], "*") != 1 || strings.Count(local, "*") != 1 {
+			errb = append(errb, &PatternError{Pattern: pattern, Err: ErrInvalidPattern})
+			continue
+		}
+		name := sel.names[0]
+		qualifier := pattern[:len(pattern)-len(name)]
+
+		matched := false
+		for _, sym := range lister.Symbols(sel.pkg) {
+			wildcard, ok := matchPattern(name, sym)
+			if !ok {
+				continue
+			}
+			matched = true
+			sym = qualifier + sym
+			if _, ok := m[sym]; ok || explicit[from.Lookup(sym)] {
+				// explicit entry
+				continue
+			}
+
+			target := strings.Replace(local, "*", wildcard, 1)
+			if transform != nil {
+				i := strings.LastIndexByte(target, '.') + 1
+				if rewritten := target[:i] + transform(target[i:]); rewritten != target {
+					mapassign(&origins, sym, target)
+					target = rewritten
+				}
+			}
+			overlap := ""
+			if owner, ok := remoteOwners[sym]; ok && owner != pattern {
+				overlap = sym
+			} else if owner, ok := localOwners[target]; ok && owner != pattern {
+				overlap = target
+			}
+			if overlap != "" {
+				errb = append(errb, &PatternError{Pattern: pattern, Symbol: overlap, Err: ErrOverlap})
+				continue
+			}
+			remoteOwners[sym] = pattern
+			localOwners[target] = pattern
+			expanded[sym] = target
+		}
+		if !matched {
+			errb = append(errb, &PatternError{Pattern: pattern, Err: ErrNoMatch})
+		}
+	}
+	return expanded, origins, errb
+}
+
+// matchPattern matches name against a pattern with a single
+// wildcard and returns the part of name that it matches.
+func matchPattern(pattern, name string) (wildcard string, ok bool) {
+	i := strings.IndexByte(pattern, '*')
+	prefix, suffix := pattern[:i], pattern[i+1:]
+	if len(name) < len(prefix)+len(suffix) ||
+		!strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+		return "", false
+	}
+	return name[len(prefix) : len(name)-len(suffix)], true
+}
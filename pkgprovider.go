@@ -2,30 +2,48 @@ package symbolassert
 
 import (
 	"fmt"
+	"go/ast"
 	"go/build"
+	"go/token"
 	"go/types"
 	"os"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
 )
 
 type PackageProvider struct {
-	GOOS      string   // target operating system
-	GOARCH    string   // target architecture
-	BuildTags []string // build tags
+	GOOS       string   // target operating system
+	GOARCH     string   // target architecture
+	BuildTags  []string // build tags
+	CgoEnabled string   // CGO_ENABLED, "0" or "1" (default of go command if empty)
 
 	// Package is used to resolve an unqualified identifier,
 	// an identifier without a package name. The caller is
 	// responsible to Load this package.
 	Package string
 
+	// ResolveAliases makes Lookup follow a type alias to
+	// the type name in the package that defines it. Only
+	// type aliases are followed, a constant or variable that
+	// re-exports another one is a distinct object.
+	ResolveAliases bool
+
+	// KeepSyntax keeps the syntax of the loaded packages, so
+	// the provider can be used with Report.Fixes.
+	KeepSyntax bool
+
 	cfg    *packages.Config
 	names  map[string]string // package name resolved to package path
 	local  map[string]string // local import resolved to package path
 	scopes map[string]*types.Scope
+	syntax map[string]*packages.Package // package path to package
 }
 
-var _ Provider = (*PackageProvider)(nil)
+var (
+	_ Lister         = (*PackageProvider)(nil)
+	_ SyntaxProvider = (*PackageProvider)(nil)
+)
 
 // Load implements the Provider interface.
 func (p *PackageProvider) Load(path string) error {
@@ -38,6 +56,9 @@ func (p *PackageProvider) Load(path string) error {
 		p.cfg = &packages.Config{
 			Mode: packages.NeedName | packages.NeedTypes,
 		}
+		if p.KeepSyntax {
+			p.cfg.Mode |= packages.NeedSyntax
+		}
 
 		buildTags := make([]string, len(p.BuildTags))
 		copy(buildTags, p.BuildTags)
@@ -50,6 +71,9 @@ func (p *PackageProvider) Load(path string) error {
 			p.cfg.Env = append(p.cfg.Env, "GOARCH="+p.GOARCH)
 			buildTags = append(buildTags, p.GOARCH)
 		}
+		if p.CgoEnabled != "" {
+			p.cfg.Env = append(p.cfg.Env, "CGO_ENABLED="+p.CgoEnabled)
+		}
 		if GOCACHE, ok := os.LookupEnv("GOCACHE"); ok {
 			p.cfg.Env = append(p.cfg.Env,
 				"GOCACHE="+GOCACHE,
@@ -98,12 +122,63 @@ func (p *PackageProvider) Load(path string) error {
 	}
 
 	p.scopes[pkg.PkgPath] = pkg.Types.Scope()
+	if p.KeepSyntax {
+		if p.syntax == nil {
+			p.syntax = make(map[string]*packages.Package)
+		}
+		p.syntax[pkg.PkgPath] = pkg
+	}
 	return nil
 }
 
+// Sizes returns the sizes of the target architecture,
+// for use in Config.
+func (p *PackageProvider) Sizes() types.Sizes {
+	goarch := p.GOARCH
+	if goarch == "" {
+		goarch = build.Default.GOARCH
+	}
+	return types.SizesFor("gc", goarch)
+}
+
 // Lookup implements the Provider interface.
 func (p *PackageProvider) Lookup(symbol string) types.Object {
-	pkg, name := splitAtLastDot(symbol)
+	obj := lookupSymbol(symbol, p.scope)
+	if p.ResolveAliases {
+		obj = resolveAlias(obj)
+	}
+	return obj
+}
+
+// Syntax implements the SyntaxProvider interface.
+func (p *PackageProvider) Syntax(obj types.Object) (*ast.File, *token.FileSet) {
+	if obj.Pkg() == nil {
+		return nil, nil
+	}
+	pkg := p.syntax[obj.Pkg().Path()]
+	if pkg == nil {
+		return nil, nil
+	}
+	return fileAt(pkg.Syntax, pkg.Fset, obj.Pos()), pkg.Fset
+}
+
+// Symbols implements the Lister interface.
+func (p *PackageProvider) Symbols(pkg string) []string {
+	if s := p.scope(pkg); s != nil {
+		return s.Names()
+	}
+	return nil
+}
+
+// scope returns the scope of a loaded package given by
+// name, path or local import.
+func (p *PackageProvider) scope(pkg string) *types.Scope {
+	return p.scopes[p.packagePath(pkg)]
+}
+
+// packagePath resolves a package given by name, path or
+// local import to its path.
+func (p *PackageProvider) packagePath(pkg string) string {
 	if pkg == "" && p.Package != "" {
 		pkg = p.Package
 	}
@@ -112,8 +187,26 @@ func (p *PackageProvider) Lookup(symbol string) types.Object {
 	} else if path, ok := p.local[pkg]; ok {
 		pkg = path
 	}
-	if s, ok := p.scopes[pkg]; ok {
-		return s.Lookup(name)
+	return pkg
+}
+
+func (p *PackageProvider) matchTag(tag string) bool {
+	if tag == "cgo" {
+		return p.CgoEnabled == "1" || p.CgoEnabled == "" && build.Default.CgoEnabled
 	}
-	return nil
+	return matchTag(p.GOOS, p.GOARCH, p.BuildTags, tag)
+}
+
+func (p *PackageProvider) platform() string {
+	goos, goarch := p.GOOS, p.GOARCH
+	if goos == "" {
+		goos = build.Default.GOOS
+	}
+	if goarch == "" {
+		goarch = build.Default.GOARCH
+	}
+	if len(p.BuildTags) > 0 {
+		return fmt.Sprintf("%s/%s tags=%s", goos, goarch, strings.Join(p.BuildTags, ","))
+	}
+	return goos + "/" + goarch
 }
@@ -1,6 +1,8 @@
 package symbolassert
 
 import (
+	"go/types"
+	"sort"
 	"testing"
 )
 
@@ -70,3 +72,98 @@ func TestPackageProvider(t *testing.T) {
 		}
 	})
 }
+
+func TestPackageProvider_Sizes(t *testing.T) {
+	for goarch, want := range map[string]int64{"386": 4, "amd64": 8} {
+		p := &PackageProvider{GOARCH: goarch}
+		if got := p.Sizes().Sizeof(types.Typ[types.Uintptr]); got != want {
+			t.Errorf("%s: got %d, want: %d", goarch, got, want)
+		}
+	}
+}
+
+func TestPackageProvider_ResolveAliases(t *testing.T) {
+	for _, resolve := range []bool{false, true} {
+		p := &PackageProvider{Package: "remotepkg", ResolveAliases: resolve}
+		if err := p.Load(remotepkgLocalImport); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, symbol := range []string{"AliasType", "AliasChain"} {
+			obj := p.Lookup(symbol)
+			if obj == nil {
+				t.Fatalf("%s is undefined", symbol)
+			}
+			want := symbol
+			if resolve {
+				want = "ByteOrder"
+			}
+			if obj.Name() != want {
+				t.Errorf("%s (resolve %t): got %s, want: %s", symbol, resolve, obj.Name(), want)
+			}
+		}
+
+		// constants are not aliases
+		if obj := p.Lookup("AliasConst"); obj == nil || obj.Name() != "AliasConst" {
+			t.Errorf("AliasConst (resolve %t): got %v", resolve, obj)
+		}
+	}
+}
+
+func TestPackageProvider_Symbols(t *testing.T) {
+	p := &PackageProvider{Package: "remotepkg"}
+	if err := p.Load(remotepkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pkg := range []string{"", "remotepkg", remotepkgLocalImport, remotepkgFullPkgPath} {
+		names := p.Symbols(pkg)
+		if !sort.StringsAreSorted(names) {
+			t.Errorf("%q: symbols are not sorted", pkg)
+		}
+		if i := sort.SearchStrings(names, "ConstInt"); i == len(names) || names[i] != "ConstInt" {
+			t.Errorf("%q: ConstInt is not listed", pkg)
+		}
+	}
+	if names := p.Symbols("unknown"); names != nil {
+		t.Errorf("got: %v, want: nil", names)
+	}
+}
+
+func TestPackageProvider_Selector(t *testing.T) {
+	p := &PackageProvider{Package: "remotepkg"}
+	if err := p.Load(remotepkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		symbol string
+		want   string // object found, empty if none
+	}{
+		{"Stat.Ino", "field Ino uint64"},
+		{"remotepkg.Stat.Ino", "field Ino uint64"},
+		{remotepkgFullPkgPath + ".Stat.Ino", "field Ino uint64"},
+		{`"` + remotepkgFullPkgPath + `".Stat.Ino`, "field Ino uint64"},
+		{"Stat.Timespec.Nsec", "field Nsec int64"},
+		{"Stat.Nsec", "field Nsec int64"},
+		{"Head.Next.Stat.Dev", "field Dev uint64"},
+		{"Receiver.Value", "func (" + remotepkgFullPkgPath + ".Receiver).Value()"},
+		{"(remotepkg.Receiver).Value", "func (" + remotepkgFullPkgPath + ".Receiver).Value()"},
+		{"(*Receiver).Pointer", "func (*" + remotepkgFullPkgPath + ".Receiver).Pointer()"},
+		{"(*Receiver).Promoted", "func (" + remotepkgFullPkgPath + ".Embedded).Promoted() int"},
+		{"Receiver.Pointer", ""},
+		{"Stat.Missing", ""},
+		{"ConstInt.Missing", ""},
+		{"(*Receiver).Pointer.Missing", ""},
+		{`"remotepkg.Stat`, ""},
+	} {
+		obj := p.Lookup(c.symbol)
+		got := ""
+		if obj != nil {
+			got = obj.String()
+		}
+		if got != c.want {
+			t.Errorf("%s: got %q, want: %q", c.symbol, got, c.want)
+		}
+	}
+}
@@ -165,8 +165,8 @@ func testLookupAliases(t *testing.T, p Provider, path string) {
 			"Uint64([]byte) uint64"+
 			"}")
 
-		if obj.Type().String() != "encoding/binary.ByteOrder" {
-			t.Error("unexpected type:", obj.Type())
+		if typ := unalias(obj.Type()); typ.String() != "encoding/binary.ByteOrder" {
+			t.Error("unexpected type:", typ)
 		}
 	})
 	t.Run("AliasConst", func(t *testing.T) {
@@ -0,0 +1,143 @@
+package symbolassert
+
+import (
+	"errors"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// A selector is a parsed symbol name. Symbols are written
+//
+//	Symbol   = [ Package "." ] Selector | Method .
+//	Selector = identifier { "." identifier } .
+//	Method   = "(" [ "*" ] [ Package "." ] identifier ")" "." identifier .
+//	Package  = identifier | import_path | string_lit .
+//
+// so a symbol denotes a package-level object, a field or
+// method selected from it like Stat_t.Timespec.Nsec, or a
+// method expression like (*T).Method. An unquoted package
+// ends at the first dot after its last slash, which makes
+// the quoted form necessary for import paths like
+// "gopkg.in/yaml.v3".Node, whose last element has a dot.
+type selector struct {
+	pkg    string   // package name or import path
+	quoted bool     // pkg is quoted, so it isn't an identifier
+	ptr    bool     // method expression on a pointer receiver
+	names  []string // identifier, then field and method names
+}
+
+var errSyntax = errors.New("invalid symbol syntax")
+
+func parseSymbol(symbol string) (selector, error) {
+	var sel selector
+	s := symbol
+	if strings.HasPrefix(s, "(") {
+		i := strings.Index(s, ").")
+		if i == -1 {
+			return sel, errSyntax
+		}
+		recv := s[1:i]
+		if strings.HasPrefix(recv, "*") {
+			sel.ptr = true
+			recv = recv[1:]
+		}
+		inner, err := parseSymbol(recv)
+		if err != nil || len(inner.names) != 1 || strings.Contains(recv, "*") {
+			return sel, errSyntax
+		}
+		sel.pkg, sel.quoted = inner.pkg, inner.quoted
+		sel.names = append(inner.names, s[i+2:])
+		if !validNames(sel.names) {
+			return sel, errSyntax
+		}
+		return sel, nil
+	}
+
+	if strings.HasPrefix(s, `"`) {
+		i := strings.Index(s, `".`)
+		if i == -1 {
+			return sel, errSyntax
+		}
+		pkg, err := strconv.Unquote(s[:i+1])
+		if err != nil {
+			return sel, errSyntax
+		}
+		sel.pkg, sel.quoted = pkg, true
+		s = s[i+2:]
+	} else {
+		slash := strings.LastIndexByte(s, '/') + 1
+		if i := strings.IndexByte(s[slash:], '.'); i != -1 {
+			sel.pkg = s[:slash+i]
+			s = s[slash+i+1:]
+		}
+	}
+	sel.names = strings.Split(s, ".")
+	if !validNames(sel.names) {
+		return sel, errSyntax
+	}
+	return sel, nil
+}
+
+func validNames(names []string) bool {
+	for _, name := range names {
+		if name == "" || strings.ContainsAny(name, `./"() `) {
+			return false
+		}
+	}
+	return true
+}
+
+// unqualified returns the selector that treats an unquoted
+// package as the identifier of a symbol in the default
+// package, so Stat_t.Ino is a field of Stat_t.
+func (s selector) unqualified() (selector, bool) {
+	if s.pkg == "" || s.quoted || strings.ContainsAny(s.pkg, "/.") {
+		return s, false
+	}
+	names := make([]string, 0, len(s.names)+1)
+	s.names = append(append(names, s.pkg), s.names...)
+	s.pkg = ""
+	return s, true
+}
+
+// lookupSymbol looks up a symbol in the scope that scopeOf
+// returns for its package, or nil if the package is unknown.
+func lookupSymbol(symbol string, scopeOf func(pkg string) *types.Scope) types.Object {
+	sel, err := parseSymbol(symbol)
+	if err != nil {
+		return nil
+	}
+	scope := scopeOf(sel.pkg)
+	if scope == nil {
+		var ok bool
+		if sel, ok = sel.unqualified(); !ok {
+			return nil
+		}
+		if scope = scopeOf(sel.pkg); scope == nil {
+			return nil
+		}
+	}
+	return sel.lookup(scope)
+}
+
+// lookup resolves the selector in the scope of its package.
+func (s selector) lookup(scope *types.Scope) types.Object {
+	obj := scope.Lookup(s.names[0])
+	for i, name := range s.names[1:] {
+		var typ types.Type
+		switch obj := obj.(type) {
+		case *types.TypeName:
+			typ = obj.Type()
+			if i == 0 && s.ptr {
+				typ = types.NewPointer(typ)
+			}
+		case *types.Var:
+			typ = obj.Type()
+		default:
+			return nil
+		}
+		obj, _, _ = types.LookupFieldOrMethod(unalias(typ), false, obj.Pkg(), name)
+	}
+	return obj
+}
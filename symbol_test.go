@@ -0,0 +1,49 @@
+package symbolassert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseSymbol(t *testing.T) {
+	for _, c := range []struct {
+		in  string
+		out selector
+		err bool
+	}{
+		{in: "Bool", out: selector{names: []string{"Bool"}}},
+		{in: "unix.Stat_t", out: selector{pkg: "unix", names: []string{"Stat_t"}}},
+		{in: "unix.Stat_t.Ino", out: selector{pkg: "unix", names: []string{"Stat_t", "Ino"}}},
+		{in: "golang.org/x/sys/unix.Stat_t.Ino", out: selector{pkg: "golang.org/x/sys/unix", names: []string{"Stat_t", "Ino"}}},
+		{in: "./internal/remotepkg.Bool", out: selector{pkg: "./internal/remotepkg", names: []string{"Bool"}}},
+		{in: `"gopkg.in/yaml.v3".Node.Kind`, out: selector{pkg: "gopkg.in/yaml.v3", quoted: true, names: []string{"Node", "Kind"}}},
+		{in: "(*T).Method", out: selector{ptr: true, names: []string{"T", "Method"}}},
+		{in: "(T).Method", out: selector{names: []string{"T", "Method"}}},
+		{in: `(*"gopkg.in/yaml.v3".Node).Decode`, out: selector{pkg: "gopkg.in/yaml.v3", quoted: true, ptr: true, names: []string{"Node", "Decode"}}},
+		{in: "unix.EPOLL*", out: selector{pkg: "unix", names: []string{"EPOLL*"}}},
+		{in: "", err: true},
+		{in: "unix.", err: true},
+		{in: "Stat_t..Ino", err: true},
+		{in: `"unix.Stat_t`, err: true},
+		{in: `"unix".`, err: true},
+		{in: "(*T)", err: true},
+		{in: "(*T).A.B", err: true},
+		{in: "(**T).M", err: true},
+	} {
+		t.Run(c.in, func(t *testing.T) {
+			out, err := parseSymbol(c.in)
+			if c.err {
+				if err == nil {
+					t.Errorf("expect error, got: %+v", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(out, c.out) {
+				t.Errorf("got %+v, want: %+v", out, c.out)
+			}
+		})
+	}
+}
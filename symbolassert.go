@@ -3,9 +3,11 @@ package symbolassert
 
 import (
 	"fmt"
-	"go/constant"
+	"go/ast"
 	"go/token"
 	"go/types"
+	"sort"
+	"strings"
 )
 
 // Provider looks up symbol names.
@@ -14,224 +16,405 @@ type Provider interface {
 	// and makes the symbols available for lookup.
 	Load(importPath string) error
 
-	// Lookup looks up a symbol.
+	// Lookup looks up a symbol, like unix.Stat_t, or a field
+	// or method selected from it, like unix.Stat_t.Ino or
+	// (*unix.Stat_t).Method. The package may be omitted or
+	// given as a quoted import path, like
+	// "golang.org/x/sys/unix".Stat_t.
 	// It must return nil if not found.
 	Lookup(symbol string) types.Object
 }
 
+// A Lister is a Provider that can enumerate symbols.
+type Lister interface {
+	Provider
+
+	// Symbols returns the sorted names of the symbols
+	// declared by a loaded package. The package is given
+	// like the package name of a symbol in Lookup and may
+	// be omitted. It returns nil if the package is unknown.
+	Symbols(pkg string) []string
+}
+
+// A SyntaxProvider is a Provider that keeps the syntax of
+// the loaded packages, so their declarations can be fixed.
+type SyntaxProvider interface {
+	Provider
+
+	// Syntax returns the syntax of the file that declares
+	// obj and the file set of its positions. It returns nil
+	// if obj isn't declared by a loaded package.
+	Syntax(obj types.Object) (*ast.File, *token.FileSet)
+}
+
 // A SymbolMap maps from a locally defined identifier to
 // an identifier that is authoritative. The package name
 // may be omitted.
+//
+// An entry may be a pattern, an identifier with a single
+// wildcard like "unix.EPOLL*": "EPOLL*". It maps every
+// symbol of the authoritative package that matches and
+// the wildcard is replaced by the part of the name it
+// matches, so "unix.SYS_*": "sys*" maps SYS_READ to
+// sysREAD. An entry for a single symbol takes precedence.
 type SymbolMap map[string]string
 
 // Resolve resolves the symbol map to an ObjectMap.
+// Patterns are expanded using the symbols listed by from,
+// which must implement Lister.
 func (m SymbolMap) Resolve(from, to Provider) (ObjectMap, error) {
-	var (
-		o    ObjectMap
-		errb errorsBuilder
-	)
-	for remote, local := range m {
+	return m.ResolveWith(from, to, nil)
+}
+
+// ResolveOptions configures SymbolMap.ResolveWith.
+type ResolveOptions struct {
+	// Transform rewrites the local names of the entries that
+	// patterns expand to, so "unix.AF_*": "AF_*" maps AF_INET
+	// to afInet with Chain(SnakeToCamel, LowerFirst).
+	// Entries for a single symbol are used as is.
+	Transform NameTransform
+
+	// Suggestions is the maximum number of similar names an
+	// UnresolvedError suggests. If zero, up to 3 names are
+	// suggested. If negative, none are.
+	Suggestions int
+}
+
+// ResolveWith is like Resolve with options, which may be nil.
+func (m SymbolMap) ResolveWith(from, to Provider, opts *ResolveOptions) (ObjectMap, error) {
+	if opts == nil {
+		opts = &ResolveOptions{}
+	}
+	suggestions := opts.Suggestions
+	if suggestions == 0 {
+		suggestions = defaultSuggestions
+	}
+	pairs, errb := m.resolve(from, to, opts.Transform, suggestions)
+	var o ObjectMap
+	for _, p := range pairs {
+		if o == nil {
+			o = make(ObjectMap)
+		}
+		o[p.From] = p.To
+	}
+	return o, errb.Build()
+}
+
+// resolve resolves the entries ordered by the authoritative
+// symbol. An object that is spelled by more than one entry
+// is resolved by the first.
+func (m SymbolMap) resolve(from, to Provider, transform NameTransform, suggestions int) (pairs ObjectPairs, errb errorsBuilder) {
+	m, origins, errb := m.expand(from, transform)
+	remotes := make([]string, 0, len(m))
+	for remote := range m {
+		remotes = append(remotes, remote)
+	}
+	sort.Strings(remotes)
+
+	resolved := make(map[types.Object]bool)
+	for _, remote := range remotes {
+		local := m[remote]
 		objFrom := from.Lookup(remote)
 		objTo := to.Lookup(local)
 		switch {
 		case objFrom == nil:
-			errb = append(errb, &UnresolvedError{from, remote})
+			errb = append(errb, unresolved(from, remote, "", suggestions))
 		case objTo == nil:
-			errb = append(errb, &UnresolvedError{to, local})
-		default:
-			if o == nil {
-				o = make(ObjectMap)
-			}
-			o[objFrom] = objTo
+			errb = append(errb, unresolved(to, local, origins[remote], suggestions))
+		case !resolved[objFrom]:
+			resolved[objFrom] = true
+			pairs = append(pairs, ObjectPair{From: objFrom, To: objTo, Path: selectorPath(remote, objFrom)})
 		}
 	}
-	return o, errb.Build()
+	return pairs, errb
 }
 
 // UnresolvedError is returned if Resolve can't lookup a symbol.
 type UnresolvedError struct {
 	Provider Provider
 	Symbol   string
+
+	// Origin is the name Symbol is transformed from,
+	// see ResolveOptions.Transform.
+	Origin string
+
+	// Package and Platform tell where the symbol is looked
+	// up, like golang.org/x/sys/unix and linux/amd64. They
+	// are empty if the provider doesn't tell.
+	Package  string
+	Platform string
+
+	// Suggestions holds names of the package that are
+	// similar to the symbol, closest first.
+	Suggestions []string
 }
 
 func (e *UnresolvedError) Error() string {
-	return fmt.Sprintf("unresolved symbol: %s", e.Symbol)
+	var b strings.Builder
+	fmt.Fprintf(&b, "unresolved symbol: %s", e.Symbol)
+	if e.Origin != "" {
+		fmt.Fprintf(&b, " (transformed from %s)", e.Origin)
+	}
+	if e.Package != "" {
+		fmt.Fprintf(&b, " in %s", e.Package)
+	}
+	if e.Platform != "" {
+		fmt.Fprintf(&b, " [%s]", e.Platform)
+	}
+	if n := len(e.Suggestions); n > 0 {
+		b.WriteString("; did you mean ")
+		if n > 1 {
+			b.WriteString(strings.Join(e.Suggestions[:n-1], ", "))
+			b.WriteString(" or ")
+		}
+		b.WriteString(e.Suggestions[n-1])
+		b.WriteString("?")
+	}
+	return b.String()
 }
 
 // A ObjectMap maps from the locally defined symbol value to
 // a value that is authoritative.
 type ObjectMap map[types.Object]types.Object
 
-// Config configures the Compare function.
-type Config struct {
-	SortByKey bool
+func objectKey(obj types.Object) string {
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
 }
 
-// Compare asserts that locally defined symbols are
-// defined the same as the package that authoritatively
-// defines them.
-// Currently the configuration parameter is ignored and
-// may be ni.
-func Compare(m ObjectMap, cfg *Config) error {
-	if cfg != nil {
-		// ignore configuration for now
-		cfg = nil
+// selectorPath returns the names a symbol selects obj by,
+// like Node.Next for remotepkg.Node.Next or Node.Next. It's
+// empty if the symbol isn't valid.
+func selectorPath(symbol string, obj types.Object) string {
+	sel, err := parseSymbol(symbol)
+	if err != nil {
+		return ""
 	}
-
-	var errb errorsBuilder
-	for from, to := range m {
-		if err := compare(from, to, cfg); err != nil {
-			errb = append(errb, err)
+	if pkg := obj.Pkg(); pkg != nil && sel.pkg != pkg.Path() && sel.pkg != pkg.Name() {
+		if unq, ok := sel.unqualified(); ok {
+			sel = unq
 		}
 	}
-	return errb.Build()
+	return strings.Join(sel.names, ".")
 }
 
-func compare(lhs, rhs types.Object, _ *Config) error {
-	switch lhs := lhs.(type) {
-	case *types.Const:
-		if rhs, ok := rhs.(*types.Const); ok {
-			return compareConst(lhs, rhs)
-		}
+// Strictness is a predefined set of checks that determines
+// when a local definition equals the authoritative one.
+type Strictness int
 
-	case *types.Func:
-		rhs, ok := rhs.(*types.Func)
-		if ok && equalFunc(lhs, rhs, false) {
-			return nil
-		}
+const (
+	// Identical requires that definitions are the same,
+	// including struct field names, tags and embedding.
+	Identical Strictness = iota
 
-	case *types.TypeName:
-		if equalType(lhs.Type(), rhs.Type()) {
-			return nil
-		}
+	// StructurallyEqual requires that definitions have the
+	// same shape. Struct fields are matched by position and
+	// their names, tags and embedding are ignored.
+	StructurallyEqual
 
-	default:
-		panic(fmt.Sprintf("unhandled type object: %T", lhs))
-	}
-
-	return &mismatchError{lhs, rhs, "type mismatch"}
-}
+	// LayoutCompatible requires that types and variables
+	// have the same memory layout, see Config.Layout.
+	LayoutCompatible
+)
 
-func compareConst(lhs, rhs *types.Const) error {
-	ltyp, ok := lhs.Type().Underlying().(*types.Basic)
-	if !ok {
-		panic("constant is not basic type (left)")
-	}
-	rtyp, ok := rhs.Type().Underlying().(*types.Basic)
-	if !ok {
-		panic("constant is not basic type (right)")
+func (s Strictness) String() string {
+	switch s {
+	case Identical:
+		return "identical"
+	case StructurallyEqual:
+		return "structurally-equal"
+	case LayoutCompatible:
+		return "layout-compatible"
 	}
+	return fmt.Sprintf("Strictness(%d)", int(s))
+}
 
-	if ltyp.Kind() != rtyp.Kind() {
-		return &mismatchError{lhs, rhs, "constant type mismatch"}
-	}
-	if constant.Compare(lhs.Val(), token.NEQ, rhs.Val()) {
-		return &mismatchError{lhs, rhs, "constant value mismatch"}
-	}
+// Config configures the Compare function.
+type Config struct {
+	// SortByKey compares symbols ordered by the authoritative
+	// symbol, so errors are reported in a stable order.
+	SortByKey bool
 
-	return nil
+	// Strictness selects the checks that are enabled. The
+	// fields below relax the selected checks further.
+	Strictness Strictness
+
+	// Struct fields are matched by name and compared on type,
+	// embedding, tag and position. The checks below can be
+	// disabled individually.
+	IgnoreFieldNames bool // match fields by position
+	IgnoreEmbedding  bool // embedded and named fields are equal
+	IgnoreFieldTags  bool // ignore struct tags
+	IgnoreFieldOrder bool // fields may be declared in any order
+
+	// StrictAliases requires that an alias is mirrored by an
+	// alias of the identical type. By default the types that
+	// aliases denote are compared.
+	StrictAliases bool
+
+	// AllowUntyped accepts an untyped constant for a typed
+	// one if its value is exactly representable by the type.
+	AllowUntyped bool
+
+	// Layout compares the memory layout of types and variables
+	// instead of their definition: size, alignment and the
+	// offset of struct fields. Field names are ignored.
+	// If Sizes is nil, the sizes of the default GOARCH are used,
+	// Check uses the sizes of the authoritative provider.
+	Layout bool
+	Sizes  types.Sizes
 }
 
-func equalFunc(lfn, rfn *types.Func, hasRecv bool) bool {
-	lsig, ok := lfn.Type().Underlying().(*types.Signature)
-	if !ok {
-		panic("function has no signature (left)")
-	}
-	rsig, ok := rfn.Type().Underlying().(*types.Signature)
-	if !ok {
-		panic("function has no signature (right)")
+// Compare asserts that locally defined symbols are
+// defined the same as the package that authoritatively
+// defines them. The configuration may be nil.
+//
+// The map is also used as a correspondence table: wherever
+// a named type of m appears, it only equals the type it maps
+// to. Other named types are compared structurally.
+func Compare(m ObjectMap, cfg *Config) error {
+	pairs := make(ObjectPairs, 0, len(m))
+	for from, to := range m {
+		pairs = append(pairs, ObjectPair{From: from, To: to})
 	}
+	return ComparePairs(pairs, cfg)
+}
 
-	if lsig.Variadic() != rsig.Variadic() ||
-		!equalTuple(lsig.Params(), rsig.Params()) ||
-		!equalTuple(lsig.Results(), rsig.Results()) {
-		return false
+// normalize returns a copy of cfg with the checks of the
+// selected strictness applied.
+func (cfg *Config) normalize() *Config {
+	var c Config
+	if cfg != nil {
+		c = *cfg
 	}
-
-	if hasRecv {
-		if lsig.Recv() == nil || rsig.Recv() == nil {
-			return false
-		}
+	switch c.Strictness {
+	case StructurallyEqual:
+		c.IgnoreFieldNames = true
+		c.IgnoreFieldTags = true
+		c.IgnoreEmbedding = true
+	case LayoutCompatible:
+		c.Layout = true
 	}
-
-	return true
+	return &c
 }
 
-func equalType(lhs, rhs types.Type) bool {
-	switch ltyp := lhs.(type) {
-	case *types.Named:
-		if !equalType(lhs.Underlying(), rhs.Underlying()) {
-			return false
-		}
-		rtyp, ok := rhs.(*types.Named)
-		return ok && equalMethods(ltyp, rtyp)
-
-	case *types.Interface:
-		rtyp, ok := rhs.(*types.Interface)
-		return ok && equalMethods(ltyp, rtyp)
-
-	case *types.Basic:
-		rtyp, ok := rhs.(*types.Basic)
-		return ok && ltyp.Kind() == rtyp.Kind()
-
-	case *types.Struct:
-		fields := ltyp.NumFields()
-		rtyp, ok := rhs.(*types.Struct)
-		if !ok || fields != rtyp.NumFields() {
-			return false
-		}
-		for i := 0; i < fields; i++ {
-			if ltyp.Field(i).Type().Underlying() != rtyp.Field(i).Type().Underlying() {
-				return false
-			}
-		}
-
-	default:
-		panic(fmt.Sprintf("unhandled type: %T", lhs))
-	}
+// MismatchKind describes what differs between two objects.
+type MismatchKind int
+
+const (
+	TypeMismatch       MismatchKind = iota // types differ
+	ObjectMismatch                         // objects are of a different kind
+	ValueMismatch                          // constant values differ
+	KindMismatch                           // constant types differ
+	FieldTypeMismatch                      // struct field types differ
+	FieldNameMismatch                      // struct field names differ
+	FieldOrderMismatch                     // struct field positions differ
+	FieldCountMismatch                     // number of struct fields differ
+	EmbeddingMismatch                      // embedded and named struct field
+	TagMismatch                            // struct field tags differ
+	MissingField                           // struct field is not defined locally
+	ExtraField                             // struct field is only defined locally
+	MissingMethod                          // method is not defined locally
+	ExtraMethod                            // method is only defined locally
+	MethodMismatch                         // method signatures differ
+	ReceiverMismatch                       // pointer and value receiver
+	AliasMismatch                          // aliases denote different types
+	SizeMismatch                           // type sizes differ
+	AlignmentMismatch                      // type alignments differ
+	OffsetMismatch                         // struct field offsets differ
+)
 
-	return true
+var mismatchKinds = [...]string{
+	TypeMismatch:       "type",
+	ObjectMismatch:     "object",
+	ValueMismatch:      "constant value",
+	KindMismatch:       "constant type",
+	FieldTypeMismatch:  "field type",
+	FieldNameMismatch:  "field name",
+	FieldOrderMismatch: "field order",
+	FieldCountMismatch: "field count",
+	EmbeddingMismatch:  "field embedding",
+	TagMismatch:        "field tag",
+	MissingField:       "missing field",
+	ExtraField:         "extra field",
+	MissingMethod:      "missing method",
+	ExtraMethod:        "extra method",
+	MethodMismatch:     "method signature",
+	ReceiverMismatch:   "method receiver",
+	AliasMismatch:      "alias",
+	SizeMismatch:       "size",
+	AlignmentMismatch:  "alignment",
+	OffsetMismatch:     "field offset",
 }
 
-func equalTuple(lhs, rhs *types.Tuple) bool {
-	n := lhs.Len()
-	if n != rhs.Len() {
-		return false
-	}
-	for i := 0; i < n; i++ {
-		if lhs.At(i).Type().Underlying() != rhs.At(i).Type().Underlying() {
-			return false
-		}
+func (k MismatchKind) String() string {
+	if k >= 0 && int(k) < len(mismatchKinds) {
+		return mismatchKinds[k]
 	}
-	return true
+	return fmt.Sprintf("MismatchKind(%d)", int(k))
 }
 
-type methods interface {
-	NumMethods() int
-	Method(i int) *types.Func
+// MismatchError is returned from Compare for each
+// difference between two objects.
+type MismatchError struct {
+	Kind MismatchKind
+	From types.Object // authoritative object
+	To   types.Object // local object
+
+	// Path is the selector of the difference, starting at
+	// the authoritative object, like Stat_t.Timespec.Nsec.
+	Path string
+
+	// ToField is the name of the local field the difference
+	// is in, if it's in a struct field. Fields may be matched
+	// by position, so it may not be the last name of Path.
+	ToField string
+
+	// FromValue and ToValue hold what differs, depending on
+	// Kind: a constant.Value, types.Type, field name or tag,
+	// field position or count, size, alignment or offset.
+	// Both are nil if not applicable.
+	FromValue, ToValue interface{}
+
+	// Reason optionally explains the difference.
+	Reason string
 }
 
-func equalMethods(lhs, rhs methods) bool {
-	methods := lhs.NumMethods()
-	if methods != rhs.NumMethods() {
-		return false
+func (e *MismatchError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Kind.String())
+	switch e.Kind {
+	case MissingField, ExtraField, MissingMethod, ExtraMethod:
+		// kind describes the difference
+	default:
+		b.WriteString(" mismatch")
 	}
-	for i := 0; i < methods; i++ {
-		if !equalFunc(lhs.Method(i), rhs.Method(i), true) {
-			return false
-		}
+	b.WriteString(": ")
+	b.WriteString(e.Path)
+	if e.FromValue != nil || e.ToValue != nil {
+		fmt.Fprintf(&b, " (%v != %v)", e.FromValue, e.ToValue)
 	}
-	return true
+	if e.Reason != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Reason)
+	}
+	return b.String()
 }
 
-type mismatchError struct {
-	from types.Object
-	to   types.Object
-	msg  string
+// UnsupportedError is returned from Compare if an object
+// or a type it refers to can't be compared.
+type UnsupportedError struct {
+	Object types.Object
+	Type   types.Type // nil if Object itself is unsupported
 }
 
-func (e *mismatchError) Error() string {
-	return fmt.Sprintf("%s (%v -> %v)", e.msg, e.from, e.to)
+func (e *UnsupportedError) Error() string {
+	if e.Type != nil {
+		return fmt.Sprintf("unsupported type: %T (%v)", e.Type, e.Object)
+	}
+	return fmt.Sprintf("unsupported object: %T (%v)", e.Object, e.Object)
 }
 
 type errorsBuilder []error
@@ -255,3 +438,9 @@ func (e *Errors) Error() string {
 	}
 	return fmt.Sprintf("%v (and %d more)", e.Errs[0], n)
 }
+
+// Unwrap returns the errors, so errors.Is and errors.As
+// match every entry.
+func (e *Errors) Unwrap() []error {
+	return e.Errs
+}
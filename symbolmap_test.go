@@ -2,12 +2,17 @@ package symbolassert
 
 import (
 	"errors"
+	"go/token"
 	"go/types"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
 func TestSymbolMap_Resolve(t *testing.T) {
-	from := &PackageProvider{Package: remotepkgLocalImport}
+	from := &PackageProvider{GOOS: "darwin", GOARCH: "arm64", Package: remotepkgLocalImport}
 	if err := from.Load(remotepkgLocalImport); err != nil {
 		t.Fatal(err)
 	}
@@ -34,13 +39,346 @@ func TestSymbolMap_Resolve(t *testing.T) {
 	}
 
 	var got *UnresolvedError
-	want := &UnresolvedError{from, "Uint"}
+	want := &UnresolvedError{
+		Provider: from,
+		Symbol:   "Uint",
+		Package:  remotepkgFullPkgPath,
+		Platform: "darwin/arm64",
+	}
 	if !errors.As(errs.Errs[0], &got) {
 		t.Fatalf("got %T, want: %T", got, want)
 	}
-	if *got != *want {
+	if !reflect.DeepEqual(got, want) {
 		t.Errorf("got %v, want: %v", got, want)
 	}
+
+	t.Run("Suggestions", func(t *testing.T) {
+		from := &PackageProvider{GOOS: "linux", GOARCH: "amd64", BuildTags: []string{"netgo"}}
+		if err := from.Load(remotepkgLocalImport); err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range []struct {
+			symbol string
+			opts   *ResolveOptions
+			want   string
+		}{
+			{"remotepkg.ConstUnit", nil, "unresolved symbol: remotepkg.ConstUnit in " + remotepkgFullPkgPath +
+				" [linux/amd64 tags=netgo]; did you mean ConstUint or ConstInt?"},
+			{"remotepkg.constint", nil, "unresolved symbol: remotepkg.constint in " + remotepkgFullPkgPath +
+				" [linux/amd64 tags=netgo]; did you mean ConstInt, ConstUint or ConstInt64?"},
+			{"remotepkg.constint", &ResolveOptions{Suggestions: 1}, "unresolved symbol: remotepkg.constint in " + remotepkgFullPkgPath +
+				" [linux/amd64 tags=netgo]; did you mean ConstInt?"},
+			{"remotepkg.constint", &ResolveOptions{Suggestions: -1}, "unresolved symbol: remotepkg.constint in " + remotepkgFullPkgPath +
+				" [linux/amd64 tags=netgo]"},
+			{"remotepkg.Zzz", nil, "unresolved symbol: remotepkg.Zzz in " + remotepkgFullPkgPath +
+				" [linux/amd64 tags=netgo]"},
+		} {
+			_, err := SymbolMap{c.symbol: "Uint"}.ResolveWith(from, to, c.opts)
+			if err == nil || err.Error() != c.want {
+				t.Errorf("%s: got %v, want: %s", c.symbol, err, c.want)
+			}
+		}
+	})
+}
+
+func TestSymbolMap_ResolvePattern(t *testing.T) {
+	from := &PackageProvider{Package: remotepkgLocalImport}
+	if err := from.Load(remotepkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+	to := &PackageProvider{Package: localpkgLocalImport}
+	if err := to.Load(localpkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Expand", func(t *testing.T) {
+		symbols := SymbolMap{
+			"ConstUntyped*":            "ConstUntyped*",
+			"ConstUntypedNegInt":       "TypedNegInt",
+			"ConstUntypedPreciseFloat": "TypedPreciseFloat",
+			"remotepkg.Var*":           "Var*",
+		}
+		objects, err := symbols.Resolve(from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for objFrom, objTo := range objects {
+			got = append(got, objFrom.Name()+"="+objTo.Name())
+		}
+		sort.Strings(got)
+		want := []string{
+			"ConstUntypedBool=ConstUntypedBool",
+			"ConstUntypedComplex=ConstUntypedComplex",
+			"ConstUntypedFloat=ConstUntypedFloat",
+			"ConstUntypedInt=ConstUntypedInt",
+			"ConstUntypedNegInt=TypedNegInt",
+			"ConstUntypedPreciseFloat=TypedPreciseFloat",
+			"ConstUntypedRune=ConstUntypedRune",
+			"ConstUntypedString=ConstUntypedString",
+			"VarError=VarError",
+			"VarInt=VarInt",
+			"VarString=VarString",
+		}
+		if !equalStrings(got, want) {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+	})
+
+	t.Run("Precedence", func(t *testing.T) {
+		// the entry is spelled differently than the symbol matched
+		symbols := SymbolMap{
+			"remotepkg.Var*": "Var*",
+			"VarInt":         "TypedUntypedInt",
+		}
+		for i := 0; i < 20; i++ {
+			objects, err := symbols.Resolve(from, to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(objects) != 3 {
+				t.Fatalf("got %d objects, want: 3", len(objects))
+			}
+			if got := objects[from.Lookup("VarInt")].Name(); got != "TypedUntypedInt" {
+				t.Fatalf("VarInt maps to %s, want: TypedUntypedInt", got)
+			}
+		}
+	})
+
+	t.Run("Transform", func(t *testing.T) {
+		symbols := SymbolMap{
+			"AF_*":          "AF_*",
+			"ConstUntyped*": "Untyped*",
+		}
+		opts := &ResolveOptions{Transform: func(name string) string {
+			if strings.HasPrefix(name, "AF_") {
+				return SnakeToCamel(name)
+			}
+			return AddPrefix("Typed")(name)
+		}}
+		objects, err := symbols.ResolveWith(from, to, opts)
+
+		var got []string
+		for objFrom, objTo := range objects {
+			got = append(got, objFrom.Name()+"="+objTo.Name())
+		}
+		sort.Strings(got)
+		want := []string{
+			"AF_INET6=AfInet6",
+			"AF_INET=AfInet",
+			"AF_UNIX=AfUnix",
+			"ConstUntypedFloat=TypedUntypedFloat",
+			"ConstUntypedInt=TypedUntypedInt",
+			"ConstUntypedRune=TypedUntypedRune",
+		}
+		if !equalStrings(got, want) {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+
+		var errs *Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("got %T, want: %T", err, errs)
+		}
+		var msgs []string
+		for _, err := range errs.Errs {
+			var ue *UnresolvedError
+			if !errors.As(err, &ue) {
+				t.Fatalf("got %T, want: %T", err, ue)
+			}
+			msgs = append(msgs, ue.Symbol+" from "+ue.Origin)
+		}
+		sort.Strings(msgs)
+		wantMsgs := []string{
+			"TypedUntypedBool from UntypedBool",
+			"TypedUntypedComplex from UntypedComplex",
+			"TypedUntypedNegInt from UntypedNegInt",
+			"TypedUntypedPreciseFloat from UntypedPreciseFloat",
+			"TypedUntypedString from UntypedString",
+		}
+		if !equalStrings(msgs, wantMsgs) {
+			t.Errorf("got: %q, want: %q", msgs, wantMsgs)
+		}
+	})
+
+	for _, c := range []struct {
+		name    string
+		from    Provider
+		symbols SymbolMap
+		want    error
+	}{
+		{"NoMatch", from, SymbolMap{"Nothing*": "Nothing*"}, ErrNoMatch},
+		{"Overlap", from, SymbolMap{"Const*": "Const*", "ConstU*": "ConstU*"}, ErrOverlap},
+		{"OverlapLocal", from, SymbolMap{"ConstInt*": "Const*", "ConstUint*": "Const*"}, ErrOverlap},
+		{"Wildcards", from, SymbolMap{"Const*Int*": "Const*"}, ErrInvalidPattern},
+		{"LocalWildcard", from, SymbolMap{"Const*": "Const"}, ErrInvalidPattern},
+		{"Lister", struct{ Provider }{from}, SymbolMap{"Const*": "Const*"}, ErrInvalidPattern},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := c.symbols.Resolve(c.from, to)
+			if !errors.Is(err, c.want) {
+				t.Fatalf("got: %v, want: %v", err, c.want)
+			}
+			var perr *PatternError
+			if !errors.As(err, &perr) {
+				t.Fatalf("got %T, want: %T", err, perr)
+			}
+		})
+	}
+}
+
+func TestDeriveSymbolMap(t *testing.T) {
+	from := &PackageProvider{}
+	if err := from.Load(remotepkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+	to := &PackageProvider{}
+	if err := to.Load(localpkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &DeriveOptions{
+		FromPackage: "remotepkg",
+		ToPackage:   "localpkg",
+		Include:     []string{"Const*", "Var*"},
+		Exclude:     []string{"ConstUntyped*", "*64"},
+	}
+	symbols, err := DeriveSymbolMap(from, to, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for remote, local := range symbols {
+		got = append(got, remote+"="+local)
+	}
+	sort.Strings(got)
+	want := []string{
+		"remotepkg.ConstBool=localpkg.ConstBool",
+		"remotepkg.ConstComplex128=localpkg.ConstComplex128",
+		"remotepkg.ConstFloat32=localpkg.ConstFloat32",
+		"remotepkg.ConstInt=localpkg.ConstInt",
+		"remotepkg.ConstRune=localpkg.ConstRune",
+		"remotepkg.ConstString=localpkg.ConstString",
+		"remotepkg.ConstUint=localpkg.ConstUint",
+		"remotepkg.VarError=localpkg.VarError",
+		"remotepkg.VarInt=localpkg.VarInt",
+		"remotepkg.VarString=localpkg.VarString",
+	}
+	if !equalStrings(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	if _, err := symbols.Resolve(from, to); err != nil {
+		t.Error(err)
+	}
+
+	t.Run("All", func(t *testing.T) {
+		symbols, err := DeriveSymbolMap(from, to, &DeriveOptions{FromPackage: "remotepkg", ToPackage: "localpkg"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for remote := range symbols {
+			if _, name := splitAtLastDot(remote); !token.IsExported(name) {
+				t.Errorf("derived unexported symbol: %s", remote)
+			}
+		}
+		if _, ok := symbols["remotepkg.Stat"]; !ok {
+			t.Error("remotepkg.Stat is not derived")
+		}
+		if _, ok := symbols["remotepkg.MismatchVarInt"]; ok {
+			t.Error("remotepkg.MismatchVarInt is derived")
+		}
+	})
+
+	t.Run("Transform", func(t *testing.T) {
+		opts := &DeriveOptions{
+			FromPackage: "remotepkg",
+			ToPackage:   "localpkg",
+			Include:     []string{"AF_*"},
+			Transform:   SnakeToCamel,
+		}
+		symbols, err := DeriveSymbolMap(from, to, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := SymbolMap{
+			"remotepkg.AF_UNIX":  "localpkg.AfUnix",
+			"remotepkg.AF_INET":  "localpkg.AfInet",
+			"remotepkg.AF_INET6": "localpkg.AfInet6",
+		}
+		if !reflect.DeepEqual(symbols, want) {
+			t.Errorf("got: %v, want: %v", symbols, want)
+		}
+	})
+
+	t.Run("BadPattern", func(t *testing.T) {
+		_, err := DeriveSymbolMap(from, to, &DeriveOptions{Include: []string{"["}})
+		if !errors.Is(err, path.ErrBadPattern) {
+			t.Errorf("got: %v, want: %v", err, path.ErrBadPattern)
+		}
+	})
+
+	t.Run("Lister", func(t *testing.T) {
+		if _, err := DeriveSymbolMap(struct{ Provider }{from}, to, nil); err == nil {
+			t.Error("expect error")
+		}
+	})
+}
+
+func TestSymbolMap_Coverage(t *testing.T) {
+	from := &PackageProvider{Package: remotepkgLocalImport}
+	if err := from.Load(remotepkgLocalImport); err != nil {
+		t.Fatal(err)
+	}
+
+	symbols := SymbolMap{
+		"ConstUntyped*":       "ConstUntyped*",
+		"remotepkg.ConstUint": "ConstUint",
+	}
+	cov, err := symbols.Coverage(from, "remotepkg.ConstU*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMapped := []string{
+		"remotepkg.ConstUint",
+		"remotepkg.ConstUntypedBool",
+		"remotepkg.ConstUntypedComplex",
+		"remotepkg.ConstUntypedFloat",
+		"remotepkg.ConstUntypedInt",
+		"remotepkg.ConstUntypedNegInt",
+		"remotepkg.ConstUntypedPreciseFloat",
+		"remotepkg.ConstUntypedRune",
+		"remotepkg.ConstUntypedString",
+	}
+	if !equalStrings(cov.Mapped, wantMapped) {
+		t.Errorf("got mapped: %v, want: %v", cov.Mapped, wantMapped)
+	}
+	wantUnmapped := []string{"remotepkg.ConstUint64"}
+	if !equalStrings(cov.Unmapped, wantUnmapped) {
+		t.Errorf("got unmapped: %v, want: %v", cov.Unmapped, wantUnmapped)
+	}
+
+	var uerr *UnmappedError
+	if err := cov.Err(); !errors.As(err, &uerr) {
+		t.Fatalf("got %T, want: %T", err, uerr)
+	}
+	if got, want := uerr.Error(), "unmapped symbol: remotepkg.ConstUint64"; got != want {
+		t.Errorf("got %q, want: %q", got, want)
+	}
+
+	cov, err = symbols.Coverage(from, "ConstUntyped*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cov.Err(); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := symbols.Coverage(from, "remotepkg.["); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("got: %v, want: %v", err, ErrInvalidPattern)
+	}
+	if _, err := (SymbolMap{"Nothing*": "Nothing*"}).Coverage(from, "*"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("got: %v, want: %v", err, ErrNoMatch)
+	}
 }
 
 func TestCompare(t *testing.T) {
@@ -92,6 +430,23 @@ func TestCompare(t *testing.T) {
 			"Interface":           "Interface",
 			"Func":                "Func",
 			"Method":              "Method",
+			"VarInt":              "VarInt",
+			"VarString":           "VarString",
+			"VarError":            "VarError",
+			"Pointer":             "Pointer",
+			"Slice":               "Slice",
+			"Array":               "Array",
+			"Map":                 "Map",
+			"Chan":                "Chan",
+			"RecvChan":            "RecvChan",
+			"Signature":           "Signature",
+			"Composite":           "Composite",
+			"Number":              "Number",
+			"Pair":                "Pair",
+			"Set":                 "Set",
+			"Entry":               "Entry",
+			"Sum":                 "Sum",
+			"Apply":               "Apply",
 		}
 		in, err := symbols.Resolve(from, to)
 		if err != nil {
@@ -148,6 +503,23 @@ func TestCompare(t *testing.T) {
 			{from: "Interface", to: "Interface", err: false},
 			{from: "Func", to: "Func", err: false},
 			{from: "Method", to: "Method", err: true},
+			{from: "VarInt", to: "MismatchVarInt", err: true},
+			{from: "VarString", to: "MismatchVarString", err: true},
+			{from: "VarError", to: "VarError", err: false},
+			{from: "Pointer", to: "MismatchPointer", err: true},
+			{from: "Slice", to: "MismatchSlice", err: true},
+			{from: "Array", to: "MismatchArray", err: true},
+			{from: "Map", to: "MismatchMap", err: true},
+			{from: "Chan", to: "Chan", err: false},
+			{from: "RecvChan", to: "MismatchRecvChan", err: true},
+			{from: "Signature", to: "MismatchSignature", err: true},
+			{from: "Composite", to: "MismatchComposite", err: true},
+			{from: "Number", to: "MismatchNumber", err: true},
+			{from: "Pair", to: "MismatchPair", err: true},
+			{from: "Set", to: "MismatchSet", err: true},
+			{from: "Entry", to: "MismatchEntry", err: true},
+			{from: "Sum", to: "MismatchSum", err: true},
+			{from: "Apply", to: "MismatchApply", err: true},
 		}
 
 		// manually resolve symbols, allocate zeroed slice
@@ -157,11 +529,11 @@ func TestCompare(t *testing.T) {
 		for i, c := range cases {
 			resolved[i].from = from.Lookup(c.from)
 			if resolved[i].from == nil {
-				t.Fatal(&UnresolvedError{from, c.from})
+				t.Fatal(&UnresolvedError{Provider: from, Symbol: c.from})
 			}
 			resolved[i].to = to.Lookup(c.to)
 			if resolved[i].to == nil {
-				t.Fatal(&UnresolvedError{to, c.to})
+				t.Fatal(&UnresolvedError{Provider: to, Symbol: c.to})
 			}
 			objMap[resolved[i].from] = resolved[i].to
 			mismatch[resolved[i].from] = c.err
@@ -177,13 +549,13 @@ func TestCompare(t *testing.T) {
 		}
 
 		for _, e := range errs.Errs {
-			var me *mismatchError
+			var me *MismatchError
 			if !errors.As(e, &me) {
 				t.Errorf("error is a %T, expected %T", e, me)
 				continue
 			}
 
-			delete(mismatch, me.from)
+			delete(mismatch, me.From)
 		}
 
 		for from, unhandledErr := range mismatch {
@@ -192,4 +564,439 @@ func TestCompare(t *testing.T) {
 			}
 		}
 	})
+	t.Run("Struct", func(t *testing.T) {
+		to := &PackageProvider{Package: localpkgLocalImport}
+		if err := to.Load(localpkgLocalImport); err != nil {
+			t.Fatal(err)
+		}
+
+		cases := []struct {
+			to    string
+			cfg   *Config
+			diffs []string
+		}{
+			{"Stat", nil, nil},
+			{"StatName", nil, []string{"field name mismatch: Stat.Ino (Ino != Inode)"}},
+			{"StatName", &Config{IgnoreFieldNames: true}, nil},
+			{"StatOrder", nil, []string{
+				"field order mismatch: Stat.Dev (0 != 1)",
+				"field order mismatch: Stat.Ino (1 != 0)",
+			}},
+			{"StatOrder", &Config{IgnoreFieldOrder: true}, nil},
+			{"StatTag", nil, []string{`field tag mismatch: Stat.Mode (json:"mode" != )`}},
+			{"StatTag", &Config{IgnoreFieldTags: true}, nil},
+			{"StatEmbedding", nil, []string{"field embedding mismatch: Stat.Timespec (true != false)"}},
+			{"StatEmbedding", &Config{IgnoreEmbedding: true}, nil},
+		}
+		for _, c := range cases {
+			objFrom, objTo := from.Lookup("Stat"), to.Lookup(c.to)
+			err := Compare(ObjectMap{objFrom: objTo}, c.cfg)
+
+			var got []string
+			var errs *Errors
+			if errors.As(err, &errs) {
+				for _, e := range errs.Errs {
+					var me *MismatchError
+					if !errors.As(e, &me) {
+						t.Fatalf("error is a %T, expected %T", e, me)
+					}
+					got = append(got, me.Error())
+				}
+			}
+			if !equalStrings(got, c.diffs) {
+				t.Errorf("%s (%+v): got %q, want: %q", c.to, c.cfg, got, c.diffs)
+			}
+		}
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		to := &PackageProvider{Package: localpkgLocalImport}
+		if err := to.Load(localpkgLocalImport); err != nil {
+			t.Fatal(err)
+		}
+
+		objFrom, objTo := from.Lookup("Stamp"), to.Lookup("StampNsec")
+		err := Compare(ObjectMap{objFrom: objTo}, nil)
+		var me *MismatchError
+		if !errors.As(err, &me) {
+			t.Fatalf("got %T, want: %T", err, me)
+		}
+		if me.Kind != FieldTypeMismatch {
+			t.Errorf("got kind %v, want: %v", me.Kind, FieldTypeMismatch)
+		}
+		if me.Path != "Stamp.Time.Nsec" {
+			t.Errorf("got path %q, want: %q", me.Path, "Stamp.Time.Nsec")
+		}
+		if me.From != objFrom || me.To != objTo {
+			t.Errorf("got objects %v -> %v, want: %v -> %v", me.From, me.To, objFrom, objTo)
+		}
+		if me.FromValue != types.Typ[types.Int64] || me.ToValue != types.Typ[types.Uint64] {
+			t.Errorf("got values %v != %v, want: int64 != uint64", me.FromValue, me.ToValue)
+		}
+	})
+
+	t.Run("Methods", func(t *testing.T) {
+		to := &PackageProvider{Package: localpkgLocalImport}
+		if err := to.Load(localpkgLocalImport); err != nil {
+			t.Fatal(err)
+		}
+
+		cases := []struct {
+			to    string
+			diffs []string
+		}{
+			{"Receiver", nil},
+			{"ReceiverKind", []string{
+				"method receiver mismatch: Receiver.Pointer (pointer != value)",
+				"method receiver mismatch: Receiver.Value (value != pointer)",
+			}},
+			{"ReceiverPromoted", []string{
+				"missing field: Receiver.Embedded",
+				"missing method: Receiver.Promoted",
+			}},
+			{"ReceiverExtra", []string{
+				"extra method: Receiver.Extra",
+			}},
+			{"ReceiverSignature", []string{
+				"method signature mismatch: Receiver.Value (func() != func() int)",
+			}},
+		}
+		for _, c := range cases {
+			objFrom, objTo := from.Lookup("Receiver"), to.Lookup(c.to)
+			err := Compare(ObjectMap{objFrom: objTo}, nil)
+
+			var got []string
+			var errs *Errors
+			if errors.As(err, &errs) {
+				for _, e := range errs.Errs {
+					got = append(got, e.Error())
+				}
+			}
+			if !equalStrings(got, c.diffs) {
+				t.Errorf("%s: got %q, want: %q", c.to, got, c.diffs)
+			}
+		}
+	})
+
+	t.Run("Selector", func(t *testing.T) {
+		to := &PackageProvider{Package: localpkgLocalImport}
+		if err := to.Load(localpkgLocalImport); err != nil {
+			t.Fatal(err)
+		}
+
+		cases := []struct {
+			from, to string
+			diffs    []string
+		}{
+			{"Stat.Ino", "StatOrder.Ino", nil},
+			{"Stat.Timespec.Nsec", "StatName.Nsec", nil},
+			{"Stat.Mode", "Stat.Dev", []string{
+				"type mismatch: Mode (uint32 != uint64)",
+			}},
+			{"(*Receiver).Pointer", "(*ReceiverExtra).Pointer", nil},
+			{"(*Receiver).Pointer", "ReceiverKind.Pointer", []string{
+				"method receiver mismatch: Pointer (pointer != value)",
+			}},
+			{"Receiver.Value", "(*ReceiverSignature).Value", []string{
+				"type mismatch: Value (func() != func() int)",
+			}},
+		}
+		for _, c := range cases {
+			objFrom, objTo := from.Lookup(c.from), to.Lookup(c.to)
+			if objFrom == nil || objTo == nil {
+				t.Fatalf("%s -> %s: unresolved", c.from, c.to)
+			}
+			err := Compare(ObjectMap{objFrom: objTo}, nil)
+
+			var got []string
+			var errs *Errors
+			if errors.As(err, &errs) {
+				for _, e := range errs.Errs {
+					got = append(got, e.Error())
+				}
+			}
+			if !equalStrings(got, c.diffs) {
+				t.Errorf("%s -> %s: got %q, want: %q", c.from, c.to, got, c.diffs)
+			}
+		}
+	})
+
+	t.Run("Correspondence", func(t *testing.T) {
+		to := &PackageProvider{Package: localpkgLocalImport}
+		if err := to.Load(localpkgLocalImport); err != nil {
+			t.Fatal(err)
+		}
+
+		cases := []struct {
+			symbols SymbolMap
+			err     bool
+		}{
+			{SymbolMap{"Fstat": "Fstat"}, false},
+			{SymbolMap{"Fstat": "FstatCopy"}, false},
+			{SymbolMap{"Fstat": "Fstat", "Stat": "Stat"}, false},
+			{SymbolMap{"Fstat": "FstatCopy", "Stat": "Stat"}, true},
+			{SymbolMap{"Fstat": "FstatCopy", "Stat": "StatCopy"}, false},
+			{SymbolMap{"Fstat": "Fstat", "Stat": "StatCopy"}, true},
+			{SymbolMap{"Node": "Node"}, false},
+			{SymbolMap{"Node": "Node", "Stat": "Stat"}, false},
+			{SymbolMap{"Node": "Node", "Stat": "StatCopy"}, true},
+			{SymbolMap{"List": "List"}, false},
+			{SymbolMap{"Head": "Head"}, false},
+			{SymbolMap{"Ints": "Ints"}, false},
+			{SymbolMap{"Pair": "Pair"}, false},
+		}
+		for _, c := range cases {
+			objects, err := c.symbols.Resolve(from, to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = Compare(objects, nil)
+			if (err != nil) != c.err {
+				t.Errorf("%v: got error %v, want error: %t", c.symbols, err, c.err)
+			}
+		}
+	})
+
+	t.Run("Alias", func(t *testing.T) {
+		to := &PackageProvider{Package: localpkgLocalImport}
+		if err := to.Load(localpkgLocalImport); err != nil {
+			t.Fatal(err)
+		}
+
+		cases := []struct {
+			from      string
+			to        string
+			err       bool
+			strictErr bool
+		}{
+			{"AliasType", "AliasType", false, false},
+			{"AliasChain", "AliasType", false, false},
+			{"AliasChain", "AliasChain", false, false},
+			{"AliasType", "ByteOrder", false, true},
+			{"AliasType", "AliasByteOrder", false, true},
+			{"AliasType", "Interface", true, true},
+		}
+		for _, c := range cases {
+			objFrom, objTo := from.Lookup(c.from), to.Lookup(c.to)
+			for _, strict := range []bool{false, true} {
+				err := Compare(ObjectMap{objFrom: objTo}, &Config{StrictAliases: strict})
+				wantErr := c.err
+				if strict {
+					wantErr = c.strictErr
+				}
+				if (err != nil) != wantErr {
+					t.Errorf("%s -> %s (strict %t): got error %v, want error: %t",
+						c.from, c.to, strict, err, wantErr)
+				}
+				var me *MismatchError
+				if strict && wantErr && (!errors.As(err, &me) || me.Kind != AliasMismatch) {
+					t.Errorf("%s -> %s: got %v, want %v", c.from, c.to, err, AliasMismatch)
+				}
+			}
+		}
+	})
+
+	t.Run("Layout", func(t *testing.T) {
+		to := &PackageProvider{Package: localpkgLocalImport}
+		if err := to.Load(localpkgLocalImport); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg := &Config{Layout: true, Sizes: from.Sizes()}
+		cases := []struct {
+			from  string
+			to    string
+			diffs []string
+		}{
+			{"Stat", "Stat", nil},
+			{"Stat", "StatName", nil},
+			{"Stat", "StatOrder", nil},
+			{"Stat", "StatLayout", []string{
+				"size mismatch: Stat (40 != 32)",
+				"size mismatch: Stat.Ino (8 != 4)",
+				"alignment mismatch: Stat.Ino (8 != 4)",
+				"field offset mismatch: Stat.Mode (16 != 12)",
+				"field offset mismatch: Stat.Timespec (24 != 16)",
+			}},
+			{"Stat", "Timespec", []string{
+				"size mismatch: Stat (40 != 16)",
+				"field count mismatch: Stat (4 != 2)",
+			}},
+			{"VarInt", "VarInt", nil},
+			{"Entry", "Entry", nil},
+			{"Ints", "Ints", nil},
+		}
+		for _, c := range cases {
+			objFrom, objTo := from.Lookup(c.from), to.Lookup(c.to)
+			err := Compare(ObjectMap{objFrom: objTo}, cfg)
+
+			var got []string
+			var errs *Errors
+			if errors.As(err, &errs) {
+				for _, e := range errs.Errs {
+					var me *MismatchError
+					if !errors.As(e, &me) {
+						t.Fatalf("error is a %T, expected %T", e, me)
+					}
+					got = append(got, me.Error())
+				}
+			}
+			if !equalStrings(got, c.diffs) {
+				t.Errorf("%s: got %q, want: %q", c.to, got, c.diffs)
+			}
+		}
+	})
+
+	t.Run("AllowUntyped", func(t *testing.T) {
+		to := &PackageProvider{Package: localpkgLocalImport}
+		if err := to.Load(localpkgLocalImport); err != nil {
+			t.Fatal(err)
+		}
+
+		cases := []struct {
+			from   string
+			to     string
+			err    bool
+			reason string
+		}{
+			{"ConstUntypedInt", "TypedUntypedInt", false, ""},
+			{"ConstUntypedRune", "TypedUntypedRune", false, ""},
+			{"ConstUntypedFloat", "TypedUntypedFloat", false, ""},
+			{"ConstUntypedBool", "TypedBool", false, ""},
+			{"ConstUint", "ConstUntypedInt", false, ""},
+			{"ConstUntypedNegInt", "TypedNegInt", true, "-1 overflows uint32"},
+			{"ConstUntypedPreciseFloat", "TypedPreciseFloat", true, "0.1 is truncated by float32"},
+			{"ConstUntypedString", "TypedUntypedInt", true, `"string" is not representable by uint32`},
+			{"ConstInt", "TypedUntypedInt", true, ""},
+		}
+		for _, c := range cases {
+			objFrom, objTo := from.Lookup(c.from), to.Lookup(c.to)
+			for _, allow := range []bool{false, true} {
+				err := Compare(ObjectMap{objFrom: objTo}, &Config{AllowUntyped: allow})
+
+				// kinds differ in every case
+				wantErr, wantReason := true, ""
+				if allow {
+					wantErr, wantReason = c.err, c.reason
+				}
+				var me *MismatchError
+				if errors.As(err, &me) != wantErr {
+					t.Errorf("%s -> %s (allow %t): got %v, want error: %t", c.from, c.to, allow, err, wantErr)
+					continue
+				}
+				if !wantErr {
+					continue
+				}
+				if me.Kind != KindMismatch {
+					t.Errorf("%s -> %s (allow %t): got kind %v, want: %v", c.from, c.to, allow, me.Kind, KindMismatch)
+				}
+				if me.Reason != wantReason {
+					t.Errorf("%s -> %s (allow %t): got reason %q, want: %q", c.from, c.to, allow, me.Reason, wantReason)
+				}
+			}
+		}
+	})
+
+	t.Run("SortByKey", func(t *testing.T) {
+		to := &PackageProvider{
+			Package:   localpkgLocalImport,
+			BuildTags: []string{"mismatch"},
+		}
+		if err := to.Load(localpkgLocalImport); err != nil {
+			t.Fatal(err)
+		}
+
+		symbols := SymbolMap{
+			"ConstBool":   "MismatchBool",
+			"ConstInt":    "MismatchInt",
+			"ConstUint":   "MismatchUint",
+			"ConstString": "ConstString",
+			"VarInt":      "MismatchVarInt",
+			"Array":       "MismatchArray",
+			"Map":         "MismatchMap",
+		}
+		objects, err := symbols.Resolve(from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"Array", "ConstBool", "ConstInt", "ConstUint", "Map", "VarInt"}
+		for i := 0; i < 5; i++ {
+			err := Compare(objects, &Config{SortByKey: true})
+			var errs *Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("got %T, want: %T", err, errs)
+			}
+			var got []string
+			for _, e := range errs.Errs {
+				got = append(got, e.(*MismatchError).From.Name())
+			}
+			if !equalStrings(got, want) {
+				t.Fatalf("got %q, want: %q", got, want)
+			}
+		}
+	})
+
+	t.Run("Strictness", func(t *testing.T) {
+		to := &PackageProvider{Package: localpkgLocalImport}
+		if err := to.Load(localpkgLocalImport); err != nil {
+			t.Fatal(err)
+		}
+
+		cases := []struct {
+			to         string
+			strictness Strictness
+			err        bool
+		}{
+			{"Stat", Identical, false},
+			{"StatName", Identical, true},
+			{"StatName", StructurallyEqual, false},
+			{"StatTag", StructurallyEqual, false},
+			{"StatEmbedding", StructurallyEqual, false},
+			{"StatLayout", StructurallyEqual, true},
+			{"StatName", LayoutCompatible, false},
+			{"StatLayout", LayoutCompatible, true},
+		}
+		for _, c := range cases {
+			objFrom, objTo := from.Lookup("Stat"), to.Lookup(c.to)
+			cfg := &Config{Strictness: c.strictness, Sizes: from.Sizes()}
+			err := Compare(ObjectMap{objFrom: objTo}, cfg)
+			if (err != nil) != c.err {
+				t.Errorf("%s (%v): got error %v, want error: %t", c.to, c.strictness, err, c.err)
+			}
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		label := types.NewLabel(token.NoPos, nil, "Label")
+		tuple := types.NewVar(token.NoPos, nil, "Tuple", types.NewTuple())
+
+		for _, obj := range []types.Object{label, tuple} {
+			err := Compare(ObjectMap{obj: obj}, nil)
+			var errs *Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("got %T, want: %T", err, errs)
+			}
+			var ue *UnsupportedError
+			if !errors.As(errs.Errs[0], &ue) {
+				t.Errorf("got %T, want: %T", errs.Errs[0], ue)
+				continue
+			}
+			if ue.Object != obj {
+				t.Errorf("got object %v, want: %v", ue.Object, obj)
+			}
+		}
+	})
+}
+
+func equalStrings(lhs, rhs []string) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+	for i := range lhs {
+		if lhs[i] != rhs[i] {
+			return false
+		}
+	}
+	return true
 }
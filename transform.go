@@ -0,0 +1,92 @@
+package symbolassert
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A NameTransform rewrites an authoritative identifier to
+// the identifier of the local symbol that mirrors it, like
+// AF_INET to afInet.
+type NameTransform func(name string) string
+
+// Lower returns name in lower case.
+func Lower(name string) string {
+	return strings.ToLower(name)
+}
+
+// Upper returns name in upper case.
+func Upper(name string) string {
+	return strings.ToUpper(name)
+}
+
+// LowerFirst returns name with the first letter in lower
+// case, so SizeofSockaddrInet4 becomes sizeofSockaddrInet4.
+func LowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	r, n := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[n:]
+}
+
+// UpperFirst returns name with the first letter in upper case.
+func UpperFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	r, n := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[n:]
+}
+
+// SnakeToCamel joins the words of name separated by
+// underscores in camel case, so AF_INET becomes AfInet.
+// Words in upper case are title cased, others only get
+// their first letter in upper case.
+func SnakeToCamel(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+		if word == strings.ToUpper(word) {
+			word = strings.ToLower(word)
+		}
+		b.WriteString(UpperFirst(word))
+	}
+	return b.String()
+}
+
+// TrimPrefix returns a transform that removes prefix.
+func TrimPrefix(prefix string) NameTransform {
+	return func(name string) string {
+		return strings.TrimPrefix(name, prefix)
+	}
+}
+
+// AddPrefix returns a transform that prepends prefix.
+func AddPrefix(prefix string) NameTransform {
+	return func(name string) string {
+		return prefix + name
+	}
+}
+
+// Rewrite returns a transform that replaces the matches
+// of re with repl, see regexp.Regexp.ReplaceAllString.
+func Rewrite(re *regexp.Regexp, repl string) NameTransform {
+	return func(name string) string {
+		return re.ReplaceAllString(name, repl)
+	}
+}
+
+// Chain returns a transform that applies transforms in order.
+func Chain(transforms ...NameTransform) NameTransform {
+	return func(name string) string {
+		for _, t := range transforms {
+			name = t(name)
+		}
+		return name
+	}
+}
@@ -0,0 +1,33 @@
+package symbolassert
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNameTransform(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		transform NameTransform
+		in, out   string
+	}{
+		{"Lower", Lower, "AF_INET", "af_inet"},
+		{"Upper", Upper, "afInet", "AFINET"},
+		{"LowerFirst", LowerFirst, "SizeofSockaddrInet4", "sizeofSockaddrInet4"},
+		{"UpperFirst", UpperFirst, "sizeofSockaddrInet4", "SizeofSockaddrInet4"},
+		{"SnakeToCamel", SnakeToCamel, "AF_INET6", "AfInet6"},
+		{"SnakeToCamel/Mixed", SnakeToCamel, "Sizeof_SockaddrInet4", "SizeofSockaddrInet4"},
+		{"SnakeToCamel/Underscores", SnakeToCamel, "_SYS__READ_", "SysRead"},
+		{"TrimPrefix", TrimPrefix("SYS_"), "SYS_READ", "READ"},
+		{"AddPrefix", AddPrefix("sys"), "Read", "sysRead"},
+		{"Rewrite", Rewrite(regexp.MustCompile(`^IFF_(\w+)$`), "iff${1}"), "IFF_UP", "iffUP"},
+		{"Chain", Chain(SnakeToCamel, LowerFirst), "AF_INET", "afInet"},
+		{"Empty", LowerFirst, "", ""},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.transform(c.in); got != c.out {
+				t.Errorf("got %q, want: %q", got, c.out)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package symbolassert
+
+import (
+	"sort"
+	"strings"
+)
+
+// defaultSuggestions is the number of suggestions reported
+// by an UnresolvedError, see ResolveOptions.Suggestions.
+const defaultSuggestions = 3
+
+// locator is implemented by the providers of this package
+// to tell where they look up symbols and for which platform.
+type locator interface {
+	packagePath(pkg string) string
+	platform() string
+	matchTag(tag string) bool
+}
+
+// unresolved returns an UnresolvedError for a symbol that
+// p can't look up, with up to n suggestions.
+func unresolved(p Provider, symbol, origin string, n int) *UnresolvedError {
+	e := &UnresolvedError{Provider: p, Symbol: symbol, Origin: origin}
+	sel, err := parseSymbol(symbol)
+	if err != nil {
+		return e
+	}
+	if l, ok := p.(locator); ok {
+		e.Package = l.packagePath(sel.pkg)
+		e.Platform = l.platform()
+	}
+	if l, ok := p.(Lister); ok && n > 0 && len(sel.names) == 1 {
+		e.Suggestions = suggest(sel.names[0], l.Symbols(sel.pkg), n)
+	}
+	return e
+}
+
+// suggest returns up to n candidates that are close to name,
+// closest first. A candidate that only differs in case is
+// closest, others are ranked by edit distance, which must
+// be at most a quarter of the length of name.
+func suggest(name string, candidates []string, n int) []string {
+	type match struct {
+		name string
+		dist int
+	}
+	var matches []match
+	limit := len(name) / 4
+	if limit < 1 {
+		limit = 1
+	}
+	for _, c := range candidates {
+		if c == name {
+			continue
+		}
+		dist := 0
+		if !strings.EqualFold(c, name) {
+			dist = 1 + editDistance(strings.ToLower(c), strings.ToLower(name))
+			if dist > limit+1 {
+				continue
+			}
+		}
+		matches = append(matches, match{c, dist})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].dist < matches[j].dist
+	})
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+	var names []string
+	for _, m := range matches {
+		names = append(names, m.name)
+	}
+	return names
+}
+
+// editDistance returns the optimal string alignment distance
+// of a and b, the Levenshtein distance that also counts the
+// transposition of adjacent characters as a single edit.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min3(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
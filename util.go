@@ -2,6 +2,9 @@ package symbolassert
 
 import (
 	"errors"
+	"go/ast"
+	"go/token"
+	"go/types"
 	"sort"
 	"strings"
 
@@ -56,6 +59,36 @@ func splitAtLastDot(s string) (before, after string) {
 	return "", s
 }
 
+// resolveAlias returns the type name that defines the type
+// an alias refers to, following alias chains. It returns obj
+// if it isn't an alias of a named type.
+func resolveAlias(obj types.Object) types.Object {
+	tn, ok := obj.(*types.TypeName)
+	if !ok || !tn.IsAlias() {
+		return obj
+	}
+	named, ok := unalias(tn.Type()).(*types.Named)
+	if !ok || named.TypeArgs().Len() > 0 {
+		// not a symbol, like int or an instantiated type
+		return obj
+	}
+	return named.Obj()
+}
+
+// fileAt returns the file of files that holds pos.
+func fileAt(files []*ast.File, fset *token.FileSet, pos token.Pos) *ast.File {
+	tf := fset.File(pos)
+	if tf == nil {
+		return nil
+	}
+	for _, f := range files {
+		if fset.File(f.Pos()) == tf {
+			return f
+		}
+	}
+	return nil
+}
+
 func buildFlags(tags []string) []string {
 	return []string{
 		"-tags=" + strings.Join(tags, ","),
@@ -0,0 +1,266 @@
+package symbolassert
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A ValueTable holds the values of symbols per target: the
+// value of a constant, the size and alignment of a type or
+// the type of a variable or function.
+type ValueTable struct {
+	Targets []Target
+	Rows    []ValueRow
+}
+
+// A ValueRow holds the values of a symbol, a cell per target.
+type ValueRow struct {
+	Symbol string
+	Cells  []ValueCell
+}
+
+// A ValueCell holds the value of a symbol on a target.
+type ValueCell struct {
+	Value   string // empty if undefined
+	Defined bool
+
+	// Differs is set if the value differs from the value
+	// most targets have, the first of them on a tie.
+	Differs bool
+}
+
+// Values builds a table of the values of symbols in the
+// authoritative package on every target. A symbol may be
+// a pattern in the syntax of path.Match, which matches
+// the exported symbols of any target. Symbols are sorted.
+func (mx *Matrix) Values(symbols ...string) (*ValueTable, error) {
+	targets := mx.targets()
+	index := make(map[Target]int, len(targets))
+	for i, t := range targets {
+		index[t] = i
+	}
+
+	var mu sync.Mutex
+	var firstErr error
+	values := make(map[string][]ValueCell)
+	mx.forEach(func(t Target) {
+		p := t.Provider(mx.From)
+		if err := p.Load(mx.From); err != nil {
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%v: %w", t, err)
+			}
+			mu.Unlock()
+			return
+		}
+		expanded, err := expandSymbols(p, symbols)
+		if err != nil {
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%v: %w", t, err)
+			}
+			mu.Unlock()
+			return
+		}
+		sizes := p.Sizes()
+		cells := make(map[string]ValueCell)
+		for _, sym := range expanded {
+			if obj := p.Lookup(sym); obj != nil {
+				cells[sym] = ValueCell{Value: objectValue(obj, sizes), Defined: true}
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, sym := range symbols {
+			if _, ok := cells[sym]; !ok && !isGlob(sym) {
+				cells[sym] = ValueCell{} // undefined
+			}
+		}
+		for sym, cell := range cells {
+			row, ok := values[sym]
+			if !ok {
+				row = make([]ValueCell, len(targets))
+				values[sym] = row
+			}
+			row[index[t]] = cell
+		}
+	})
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	vt := &ValueTable{Targets: targets}
+	for sym, cells := range values {
+		markDifferences(cells)
+		vt.Rows = append(vt.Rows, ValueRow{Symbol: sym, Cells: cells})
+	}
+	sort.Slice(vt.Rows, func(i, j int) bool {
+		return vt.Rows[i].Symbol < vt.Rows[j].Symbol
+	})
+	return vt, nil
+}
+
+// expandSymbols returns the symbols with their patterns
+// replaced by the exported symbols they match, qualified
+// like the pattern. The package of a pattern must be loaded.
+func expandSymbols(p Lister, symbols []string) ([]string, error) {
+	var expanded []string
+	for _, sym := range symbols {
+		if !isGlob(sym) {
+			expanded = append(expanded, sym)
+			continue
+		}
+		sel, err := parseSymbol(sym)
+		if err != nil || len(sel.names) != 1 {
+			return nil, &PatternError{Pattern: sym, Err: ErrInvalidPattern}
+		}
+		pattern := sel.names[0]
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, &PatternError{Pattern: sym, Err: ErrInvalidPattern}
+		}
+		qualifier := sym[:len(sym)-len(pattern)]
+
+		names := p.Symbols(sel.pkg)
+		if len(names) == 0 {
+			return nil, &PatternError{Pattern: sym, Err: fmt.Errorf("package %s isn't loaded", sel.pkg)}
+		}
+		for _, name := range names {
+			if ok, _ := path.Match(pattern, name); ok && token.IsExported(name) {
+				expanded = append(expanded, qualifier+name)
+			}
+		}
+	}
+	return expanded, nil
+}
+
+func isGlob(symbol string) bool {
+	return strings.ContainsAny(symbol, "*?[")
+}
+
+func objectValue(obj types.Object, sizes types.Sizes) string {
+	switch obj := obj.(type) {
+	case *types.Const:
+		return obj.Val().ExactString()
+	case *types.TypeName:
+		if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+			return "generic"
+		}
+		return fmt.Sprintf("size=%d align=%d", sizes.Sizeof(obj.Type()), sizes.Alignof(obj.Type()))
+	}
+	return types.TypeString(obj.Type(), (*types.Package).Name)
+}
+
+// markDifferences marks the cells that differ from the
+// value most cells have.
+func markDifferences(cells []ValueCell) {
+	if len(cells) == 0 {
+		return
+	}
+	count := make(map[ValueCell]int)
+	for _, c := range cells {
+		count[c]++
+	}
+	majority := cells[0]
+	for _, c := range cells[1:] {
+		if count[c] > count[majority] {
+			majority = c
+		}
+	}
+	for i := range cells {
+		cells[i].Differs = cells[i] != majority
+	}
+}
+
+// WriteMarkdown writes the table as a Markdown table.
+// Values that differ are in bold, undefined symbols are
+// shown as a dash.
+func (vt *ValueTable) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Symbol |")
+	for _, t := range vt.Targets {
+		fmt.Fprintf(&b, " %s |", t)
+	}
+	b.WriteString("\n|---|")
+	b.WriteString(strings.Repeat("---|", len(vt.Targets)))
+	b.WriteString("\n")
+	for _, row := range vt.Rows {
+		fmt.Fprintf(&b, "| %s |", row.Symbol)
+		for _, c := range row.Cells {
+			v := strings.ReplaceAll(c.Value, "|", `\|`)
+			if !c.Defined {
+				v = "-"
+			}
+			if c.Differs {
+				v = "**" + v + "**"
+			}
+			fmt.Fprintf(&b, " %s |", v)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteCSV writes the table as CSV with a header row.
+// Values that differ are marked by a trailing asterisk.
+func (vt *ValueTable) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"symbol"}
+	for _, t := range vt.Targets {
+		header = append(header, t.String())
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range vt.Rows {
+		record := []string{row.Symbol}
+		for _, c := range row.Cells {
+			v := c.Value
+			if c.Differs {
+				v += "*"
+			}
+			record = append(record, v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the table as JSON.
+func (vt *ValueTable) WriteJSON(w io.Writer) error {
+	type cell struct {
+		Target  string `json:"target"`
+		Value   string `json:"value"`
+		Defined bool   `json:"defined"`
+		Differs bool   `json:"differs"`
+	}
+	type row struct {
+		Symbol string `json:"symbol"`
+		Cells  []cell `json:"cells"`
+	}
+	rows := make([]row, 0, len(vt.Rows))
+	for _, r := range vt.Rows {
+		cells := make([]cell, len(r.Cells))
+		for i, c := range r.Cells {
+			cells[i] = cell{vt.Targets[i].String(), c.Value, c.Defined, c.Differs}
+		}
+		rows = append(rows, row{r.Symbol, cells})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Rows []row `json:"rows"`
+	}{rows})
+}
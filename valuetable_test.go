@@ -0,0 +1,131 @@
+package symbolassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMatrix_Values(t *testing.T) {
+	mx := &Matrix{
+		Targets: []Target{
+			{GOOS: "linux", GOARCH: "amd64"},
+			{GOOS: "linux", GOARCH: "386"},
+			{GOOS: "darwin", GOARCH: "arm64"},
+		},
+		From: remotepkgLocalImport,
+	}
+	vt, err := mx.Values("ConstInt", "Stat", "Uint", "ConstUntyped*F*", "Missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var md bytes.Buffer
+	if err := vt.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	want := `| Symbol | linux/amd64 | linux/386 | darwin/arm64 |
+|---|---|---|---|
+| ConstInt | 1 | 1 | 1 |
+| ConstUntypedFloat | 1 | 1 | 1 |
+| ConstUntypedPreciseFloat | 1/10 | 1/10 | 1/10 |
+| Missing | - | - | - |
+| Stat | size=40 align=8 | **size=36 align=4** | size=40 align=8 |
+| Uint | size=8 align=8 | **size=4 align=4** | **-** |
+`
+	if got := md.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	var csv bytes.Buffer
+	if err := vt.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(csv.String(), "\n")
+	if got, want := lines[0], "symbol,linux/amd64,linux/386,darwin/arm64"; got != want {
+		t.Errorf("got %q, want: %q", got, want)
+	}
+	if got, want := lines[6], "Uint,size=8 align=8,size=4 align=4*,*"; got != want {
+		t.Errorf("got %q, want: %q", got, want)
+	}
+
+	var js bytes.Buffer
+	if err := vt.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Rows []struct {
+			Symbol string
+			Cells  []struct {
+				Target  string
+				Value   string
+				Defined bool
+				Differs bool
+			}
+		}
+	}
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Rows) != 6 {
+		t.Fatalf("got %d rows, want: 6", len(decoded.Rows))
+	}
+	c := decoded.Rows[4].Cells[1]
+	if decoded.Rows[4].Symbol != "Stat" || c.Target != "linux/386" || c.Value != "size=36 align=4" || !c.Defined || !c.Differs {
+		t.Errorf("got %s: %+v", decoded.Rows[4].Symbol, c)
+	}
+}
+
+func TestMatrix_ValuesPatterns(t *testing.T) {
+	mx := &Matrix{
+		Targets: []Target{{GOOS: "linux", GOARCH: "amd64"}},
+		From:    remotepkgLocalImport,
+	}
+
+	quoted := `"github.com/dwlnetnl/symbolassert/internal/remotepkg".ConstUntyped*F*`
+	vt, err := mx.Values(quoted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, row := range vt.Rows {
+		got = append(got, row.Symbol)
+	}
+	want := []string{
+		`"github.com/dwlnetnl/symbolassert/internal/remotepkg".ConstUntypedFloat`,
+		`"github.com/dwlnetnl/symbolassert/internal/remotepkg".ConstUntypedPreciseFloat`,
+	}
+	if !equalStrings(got, want) {
+		t.Errorf("got %q, want: %q", got, want)
+	}
+
+	// package isn't loaded
+	_, err = mx.Values("os.O_*")
+	var pe *PatternError
+	if !errors.As(err, &pe) || pe.Pattern != "os.O_*" {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func Test_markDifferences(t *testing.T) {
+	a := ValueCell{Value: "A", Defined: true}
+	b := ValueCell{Value: "B", Defined: true}
+	cases := []struct {
+		cells   []ValueCell
+		differs []bool
+	}{
+		{[]ValueCell{a, b, b}, []bool{true, false, false}},
+		{[]ValueCell{a, b, b, a}, []bool{false, true, true, false}},
+		{[]ValueCell{b, {}, {}, b}, []bool{false, true, true, false}},
+	}
+	for _, c := range cases {
+		markDifferences(c.cells)
+		for i, cell := range c.cells {
+			if cell.Differs != c.differs[i] {
+				t.Errorf("%+v: cell %d differs: %t, want: %t", c.cells, i, cell.Differs, c.differs[i])
+			}
+		}
+	}
+}